@@ -1,81 +1,471 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/format"
+	"github.com/pbartkowicz/scheduler/internal/grid"
+	"github.com/pbartkowicz/scheduler/internal/ical"
+	"github.com/pbartkowicz/scheduler/internal/results"
+	"github.com/pbartkowicz/scheduler/internal/server"
+	"github.com/pbartkowicz/scheduler/internal/storage"
 	"github.com/pbartkowicz/scheduler/internal/university"
+	"github.com/pbartkowicz/scheduler/internal/validation"
 	"github.com/pbartkowicz/scheduler/internal/xlsx"
 )
 
 func main() {
+	// The first argument can be a mode: validate - only validate input files, serve - start the REST API,
+	// runs - list, compare and reload runs saved in a store
+	mode := "enroll"
+	args := os.Args[1:]
+	if len(args) > 0 && (args[0] == "validate" || args[0] == "serve" || args[0] == "runs") {
+		mode, args = args[0], args[1:]
+	}
+	switch mode {
+	case "serve":
+		serve(args)
+		return
+	case "runs":
+		runs(args)
+		return
+	}
+
 	gf := flag.String("groups", "./example/groups.xlsx", "Path to file containing groups")
 	sd := flag.String("students", "./example/students", "Path to directory containing students")
 	psf := flag.String("priority", "./example/priority_students.xlsx", "Path to file containing priority students")
+	rf := flag.String("rooms", "", "Path to file containing rooms with their capacities, optional")
+	ssf := flag.String("subjects", "", "Path to file containing subjects chosen by students, optional")
 	rd := flag.String("result", "./example/result", "Path to the directory where the results will be saved")
+	of := flag.String("format", "xlsx", "Format of the results: xlsx, csv")
+	om := flag.String("output", "files", "Layout of the results: files - one file per student and subject, workbook - one results.xlsx workbook")
+	cd := flag.String("calendars", "", "Path to the directory where iCalendar files of students and groups will be saved, optional")
+	td := flag.String("timetables", "", "Path to the directory where weekly timetables of students and teachers will be saved, optional")
+	df := flag.String("document", "", "Path to a JSON or YAML file where the whole schedule will be saved, optional")
+	ed := flag.String("end", "", "End date of the semester, format: 06-30-20 (30th of June 2020)")
+	sn := flag.String("solver", "greedy", "Algorithm used to enroll students: greedy, flow, search, bidding")
+	smn := flag.String("satisfaction", "linear", "Model used to calculate students' happiness: linear, exponential, borda, points")
+	fp := flag.String("fairness", "none", "Policy used to distribute happiness between students: none, maxmin, leximin")
+	stf := flag.String("store", "", "Path to a file where the schedule, students and the run will be saved, optional")
+	inc := flag.String("incremental", "", "ID of a run saved in the store whose groups are kept, only students affected by changes are enrolled again, optional")
+	bl := flag.String("baseline", "", "ID of a run saved in the store whose groups students keep unless a move gives them more happiness than churn, optional")
+	ch := flag.Float64("churn", 10, "Penalty in happiness points for every group which differs from the baseline")
+	bg := flag.Int("budget", 0, "Points which every student can bid on groups, when set students' files contain bids instead of priorities, optional")
+
+	flag.CommandLine.Parse(args)
+
+	if *inc != "" {
+		// Incremental enrollment always uses the flow solver and no fairness policy, see Schedule.Reenroll
+		var err error
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "solver" || f.Name == "fairness" {
+				err = fmt.Errorf("-%s cannot be used with -incremental", f.Name)
+			}
+		})
+		if err != nil {
+			fmt.Printf("Read flags: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	gt, sts, pt, err := readTables(*gf, *sd, *psf)
+	if err != nil {
+		fmt.Printf("Read files: %s\n", err.Error())
+		os.Exit(1)
+	}
+	for _, st := range sts {
+		st.Budget = *bg
+	}
+	var rt *validation.Table
+	if *rf != "" {
+		if rt, err = readTable(*rf); err != nil {
+			fmt.Printf("Read files: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	rep := validation.Validate(gt, sts, pt, rt)
+	if mode == "validate" || !rep.OK() {
+		printReport(rep)
+		if !rep.OK() {
+			os.Exit(1)
+		}
+		return
+	}
 
-	flag.Parse()
+	out, err := format.New(*of)
+	if err != nil {
+		fmt.Printf("Read format: %s\n", err.Error())
+		os.Exit(1)
+	}
 
-	sch, err := readSchedule(*gf)
+	sch, err := university.NewSchedule(gt.Rows)
 	if err != nil {
 		fmt.Printf("Read groups: %s\n", err.Error())
 		os.Exit(1)
 	}
 
-	students, err := readStudents(*sd)
+	if rt != nil {
+		if err := readRooms(rt.Rows, sch); err != nil {
+			fmt.Printf("Read rooms: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if *ed != "" {
+		end, err := university.ParseDate(*ed)
+		if err != nil {
+			fmt.Printf("Read end date: %s\n", err.Error())
+			os.Exit(1)
+		}
+		sch.SetEndDate(end)
+	}
+
+	sch.Solver, err = university.NewSolver(*sn)
+	if err != nil {
+		fmt.Printf("Read solver: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	sch.Satisfaction, err = university.NewSatisfactionModel(*smn)
+	if err != nil {
+		fmt.Printf("Read satisfaction model: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	sch.Fairness, err = university.NewFairnessPolicy(*fp)
+	if err != nil {
+		fmt.Printf("Read fairness policy: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	students, err := readStudents(sts)
 	if err != nil {
 		fmt.Printf("Read students: %s\n", err.Error())
 		os.Exit(1)
 	}
 
-	if err := readPriorityStudents(*psf, students); err != nil {
+	if err := readPriorityStudents(pt.Rows, students); err != nil {
 		fmt.Printf("Read priority students: %s\n", err.Error())
 		os.Exit(1)
 	}
 
-	sch.Enroll(students)
+	if *ssf != "" {
+		if err := readStudentsSubjects(*ssf, students); err != nil {
+			fmt.Printf("Read students' subjects: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	var res *university.EnrollResult
+	if *inc != "" {
+		b, err := readBaseline(*stf, *inc)
+		if err != nil {
+			fmt.Printf("Read baseline: %s\n", err.Error())
+			os.Exit(1)
+		}
+		res, err = sch.Reenroll(students, b)
+		if err != nil {
+			fmt.Printf("Enroll students: %s\n", err.Error())
+			os.Exit(1)
+		}
+	} else {
+		if *bl != "" {
+			if sch.Baseline, err = readBaseline(*stf, *bl); err != nil {
+				fmt.Printf("Read baseline: %s\n", err.Error())
+				os.Exit(1)
+			}
+			sch.Churn = *ch
+		}
+		res, err = sch.Enroll(students)
+		if err != nil {
+			fmt.Printf("Enroll students: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	fmt.Printf("Minimum happiness: %.2f, median: %.2f, Gini coefficient: %.4f\n", res.Fairness.Min, res.Fairness.Median, res.Fairness.Gini)
+	if res.Changes != nil {
+		fmt.Printf("%d changes\n", len(res.Changes))
+		for _, c := range res.Changes {
+			fmt.Println(strings.Join(c.Save(), "\t"))
+		}
+	}
 
-	if err := saveStudents(students, *rd); err != nil {
-		fmt.Printf("Save students: %s\n", err.Error())
+	if err := saveResults(out, *om, sch, students, res, *rd); err != nil {
+		fmt.Printf("Save results: %s\n", err.Error())
 		os.Exit(1)
 	}
-	if err := saveSubjects(sch, *rd); err != nil {
-		fmt.Printf("Save subjects: %s\n", err.Error())
+	if *cd != "" {
+		if err := saveCalendars(sch, students, *cd); err != nil {
+			fmt.Printf("Save calendars: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	if *td != "" {
+		if err := saveTimetables(sch, students, *td); err != nil {
+			fmt.Printf("Save timetables: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	if *df != "" {
+		if err := document.New(sch, students).Save(*df); err != nil {
+			fmt.Printf("Save schedule document: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+	if *stf != "" {
+		p := &storage.Parameters{Solver: *sn, Satisfaction: *smn, Fairness: *fp, End: *ed, Budget: *bg}
+		if *inc != "" {
+			p.Solver, p.Fairness, p.Baseline = "flow", "none", *inc
+		}
+		if *bl != "" {
+			p.Baseline, p.Churn = *bl, *ch
+		}
+		id, err := saveRun(*stf, *gf, p, sch, students, res)
+		if err != nil {
+			fmt.Printf("Save run: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("Saved run %s\n", id)
+	}
+}
+
+// runs lists runs saved in a store, compares two runs or saves results of a run again.
+func runs(args []string) {
+	fs := flag.NewFlagSet("runs", flag.ExitOnError)
+	stf := fs.String("store", "./scheduler.db", "Path to a file with saved runs")
+	cmp := fs.String("compare", "", "IDs of two runs separated by a comma, students whose groups differ are printed")
+	ld := fs.String("load", "", "ID of a run whose results will be saved again")
+	rd := fs.String("result", "./example/result", "Path to the directory where the results of a loaded run will be saved")
+	of := fs.String("format", "xlsx", "Format of the results: xlsx, csv")
+	om := fs.String("output", "files", "Layout of the results: files - one file per student and subject, workbook - one results.xlsx workbook")
+	fs.Parse(args)
+
+	st, err := storage.Open(*stf)
+	if err != nil {
+		fmt.Printf("Open store: %s\n", err.Error())
 		os.Exit(1)
 	}
+	defer st.Close()
+
+	switch {
+	case *cmp != "":
+		ids := strings.Split(*cmp, ",")
+		if len(ids) != 2 {
+			fmt.Printf("Read runs: expected two IDs, got %d\n", len(ids))
+			os.Exit(1)
+		}
+		a, err := st.Run(strings.TrimSpace(ids[0]))
+		if err != nil {
+			fmt.Printf("Read runs: %s\n", err.Error())
+			os.Exit(1)
+		}
+		b, err := st.Run(strings.TrimSpace(ids[1]))
+		if err != nil {
+			fmt.Printf("Read runs: %s\n", err.Error())
+			os.Exit(1)
+		}
+		printRuns([]*storage.Run{a, b})
+		changes := storage.Compare(a, b)
+		fmt.Printf("\n%d changes\n", len(changes))
+		for _, c := range changes {
+			fmt.Println(strings.Join(c.Save(), "\t"))
+		}
+	case *ld != "":
+		out, err := format.New(*of)
+		if err != nil {
+			fmt.Printf("Read format: %s\n", err.Error())
+			os.Exit(1)
+		}
+		r, err := st.Run(*ld)
+		if err != nil {
+			fmt.Printf("Read run: %s\n", err.Error())
+			os.Exit(1)
+		}
+		sch, students, res, err := r.Restore()
+		if err != nil {
+			fmt.Printf("Read run: %s\n", err.Error())
+			os.Exit(1)
+		}
+		if err := saveResults(out, *om, sch, students, res, *rd); err != nil {
+			fmt.Printf("Save results: %s\n", err.Error())
+			os.Exit(1)
+		}
+	default:
+		rs, err := st.Runs()
+		if err != nil {
+			fmt.Printf("Read runs: %s\n", err.Error())
+			os.Exit(1)
+		}
+		printRuns(rs)
+	}
+}
+
+// printRuns prints one line with parameters and outcomes of every run.
+func printRuns(rs []*storage.Run) {
+	fmt.Println("id\tcreated\tschedule\tsolver\tsatisfaction\tfairness\tstudents\tunassigned\tmin\tmedian\tgini")
+	for _, r := range rs {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%.2f\t%.2f\t%.4f\n",
+			r.ID, r.Created.Format("2006-01-02 15:04"), r.Schedule,
+			r.Parameters.Solver, r.Parameters.Satisfaction, r.Parameters.Fairness,
+			len(r.Document.Students), len(r.Unassigned), r.Fairness.Min, r.Fairness.Median, r.Fairness.Gini)
+	}
 }
 
-func readSchedule(gf string) (*university.Schedule, error) {
-	g, err := xlsx.Read(gf, true)
+// readBaseline reads run id from store stf as a baseline of enrollment.
+func readBaseline(stf, id string) (*university.Baseline, error) {
+	if stf == "" {
+		return nil, errors.New("a baseline requires a store")
+	}
+	st, err := storage.Open(stf)
+	if err != nil {
+		return nil, err
+	}
+	defer st.Close()
+	r, err := st.Run(id)
 	if err != nil {
 		return nil, err
 	}
-	s, err := university.NewSchedule(g)
+	sch, students, _, err := r.Restore()
 	if err != nil {
 		return nil, err
 	}
-	return s, nil
+	return &university.Baseline{Schedule: sch, Students: students}, nil
+}
+
+// saveRun saves the schedule, students and outcomes of enrollment in store stf.
+// The schedule and students are saved under the name of groups file gf.
+func saveRun(stf, gf string, p *storage.Parameters, sch *university.Schedule, students []*university.Student, res *university.EnrollResult) (string, error) {
+	st, err := storage.Open(stf)
+	if err != nil {
+		return "", err
+	}
+	defer st.Close()
+	n := strings.TrimSuffix(filepath.Base(gf), filepath.Ext(gf))
+	if err := st.SaveSchedule(n, sch); err != nil {
+		return "", err
+	}
+	if err := st.SaveStudents(n, students); err != nil {
+		return "", err
+	}
+	r := storage.NewRun(n, p, sch, students, res)
+	if err := st.SaveRun(r); err != nil {
+		return "", err
+	}
+	return r.ID, nil
+}
+
+// saveResults saves results in layout om: files or workbook.
+func saveResults(out format.Format, om string, sch *university.Schedule, students []*university.Student, res *university.EnrollResult, rd string) error {
+	switch om {
+	case "files":
+		return saveFiles(out, sch, students, res, rd)
+	case "workbook":
+		return saveWorkbook(sch, students, res, rd)
+	}
+	return errors.New("incorrect output, available outputs: files, workbook")
+}
+
+// serve starts the REST API which enrolls students from uploaded files, see package server.
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address on which the server listens")
+	ttl := fs.Duration("ttl", 24*time.Hour, "Time after the last request to a job after which the job is removed, 0 keeps jobs until they are deleted")
+	fs.Parse(args)
+
+	s := server.New()
+	s.TTL = *ttl
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		// Uploaded files and downloaded results may take a while on slow connections
+		ReadTimeout:  time.Minute,
+		WriteTimeout: time.Minute,
+		IdleTimeout:  2 * time.Minute,
+	}
+	fmt.Printf("Listening on %s\n", *addr)
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Printf("Serve: %s\n", err.Error())
+		os.Exit(1)
+	}
 }
 
-func readStudents(sd string) ([]*university.Student, error) {
+// readTables reads groups file, all files from students directory and priority students file.
+func readTables(gf, sd, psf string) (*validation.Table, []*validation.Table, *validation.Table, error) {
+	gt, err := readTable(gf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	p, err := filepath.Abs(sd)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	sfs, err := ioutil.ReadDir(p)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
-	var students []*university.Student
+	var sts []*validation.Table
 	for _, sf := range sfs {
-		pref, err := xlsx.Read(filepath.Join(sd, sf.Name()), true)
+		st, err := readTable(filepath.Join(sd, sf.Name()))
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
+		}
+		sts = append(sts, st)
+	}
+	pt, err := readTable(psf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return gt, sts, pt, nil
+}
+
+// readTable reads a file using a format based on its extension.
+func readTable(n string) (*validation.Table, error) {
+	f, err := format.ForFile(n)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n, err)
+	}
+	s, rows, err := f.ReadSheet(n, true)
+	if err != nil {
+		return nil, err
+	}
+	return &validation.Table{
+		File:   n,
+		Sheet:  s,
+		Header: 1,
+		Rows:   rows,
+	}, nil
+}
+
+func printReport(rep *validation.Report) {
+	for _, d := range rep.Diagnostics {
+		fmt.Println(d)
+	}
+	fmt.Printf("Found %d problems\n", len(rep.Diagnostics))
+}
+
+// readStudents creates students from their files, files contain bids when a table has a budget.
+func readStudents(sts []*validation.Table) ([]*university.Student, error) {
+	var students []*university.Student
+	for _, t := range sts {
+		if t.Budget > 0 {
+			st, err := university.NewBiddingStudent(t.Rows, t.StudentName(), t.Budget)
+			if err != nil {
+				return nil, err
+			}
+			students = append(students, st)
+			continue
 		}
-		st, err := university.NewStudent(pref, sf.Name())
+		st, err := university.NewStudent(t.Rows, filepath.Base(t.File))
 		if err != nil {
 			return nil, err
 		}
@@ -84,11 +474,19 @@ func readStudents(sd string) ([]*university.Student, error) {
 	return students, nil
 }
 
-func readPriorityStudents(psf string, students []*university.Student) error {
-	ps, err := xlsx.Read(psf, true)
-	if err != nil {
-		return err
+// readRooms sets rooms of a schedule and checks if capacities of groups do not exceed capacities of their rooms.
+func readRooms(rs [][]string, schedule *university.Schedule) error {
+	for _, r := range rs {
+		room, err := university.NewRoom(r)
+		if err != nil {
+			return err
+		}
+		schedule.Rooms = append(schedule.Rooms, room)
 	}
+	return schedule.ValidateRooms()
+}
+
+func readPriorityStudents(ps [][]string, students []*university.Student) error {
 	for _, p := range ps {
 		var found bool
 		for _, st := range students {
@@ -105,26 +503,155 @@ func readPriorityStudents(psf string, students []*university.Student) error {
 	return nil
 }
 
-func saveStudents(students []*university.Student, p string) error {
+func readStudentsSubjects(ssf string, students []*university.Student) error {
+	ss, err := format.Read(ssf, true)
+	if err != nil {
+		return err
+	}
+	for _, st := range students {
+		st.Subjects = []string{}
+	}
+	for _, s := range ss {
+		var found bool
+		for _, st := range students {
+			if s[0] == st.Name {
+				st.Subjects = append(st.Subjects, s[1])
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("missing %s student", s[0])
+		}
+	}
+	return nil
+}
+
+// saveFiles saves one file per student, one file per subject and files with unassigned students, waitlists and the fairness report.
+// Changes compared to a baseline are saved only when a baseline is used, after incremental enrollment or with -baseline.
+func saveFiles(f format.Format, schedule *university.Schedule, students []*university.Student, res *university.EnrollResult, p string) error {
+	if err := saveStudents(f, students, p); err != nil {
+		return fmt.Errorf("students: %w", err)
+	}
+	if err := saveSubjects(f, schedule, p); err != nil {
+		return fmt.Errorf("subjects: %w", err)
+	}
+	if err := f.Write("unassigned", p, "Unassigned", res.Save()); err != nil {
+		return fmt.Errorf("unassigned students: %w", err)
+	}
+	if err := f.Write("waitlists", p, "Waitlists", results.Waitlists(schedule)); err != nil {
+		return fmt.Errorf("waitlists: %w", err)
+	}
+	if res.Changes != nil {
+		if err := f.Write("changes", p, "Changes", res.SaveChanges()); err != nil {
+			return fmt.Errorf("changes: %w", err)
+		}
+	}
+	if err := f.Write("fairness", p, "Fairness", res.Fairness.Save()); err != nil {
+		return fmt.Errorf("fairness report: %w", err)
+	}
+	ts, hs := results.Teachers(schedule)
+	if err := f.Write("teachers", p, "Teachers", ts); err != nil {
+		return fmt.Errorf("teachers: %w", err)
+	}
+	if err := f.Write("teachers", p, "Hours", hs); err != nil {
+		return fmt.Errorf("teachers' hours: %w", err)
+	}
+	ms, rs := results.Occupancy(schedule)
+	if err := f.Write("occupancy", p, "Occupancy", ms); err != nil {
+		return fmt.Errorf("occupancy: %w", err)
+	}
+	if err := f.Write("occupancy", p, "Rooms", rs); err != nil {
+		return fmt.Errorf("rooms: %w", err)
+	}
+	return nil
+}
+
+// saveWorkbook saves all results in one results.xlsx workbook, see results.Workbook.
+func saveWorkbook(schedule *university.Schedule, students []*university.Student, res *university.EnrollResult, p string) error {
+	w, err := xlsx.CreateWorkbook("results", p)
+	if err != nil {
+		return err
+	}
+	if err := results.Workbook(w, schedule, students, res); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func saveStudents(f format.Format, students []*university.Student, p string) error {
 	for _, st := range students {
-		if err := xlsx.Write(st.Name, p, st.Name, st.Save()); err != nil {
+		if err := f.Write(st.Name, p, st.Name, st.Save()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveSubjects(f format.Format, schedule *university.Schedule, p string) error {
+	for _, sub := range schedule.Subjects {
+		saveGroups(f, sub.Name, p, sub.Groups)
+		saveGroups(f, sub.Name, p, sub.Lectures)
+	}
+	return nil
+}
+
+func saveGroups(f format.Format, sn, p string, grs []*university.Group) error {
+	for _, g := range grs {
+		if err := f.Write(sn, p, g.Name, g.Save()); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func saveSubjects(schedule *university.Schedule, p string) error {
+// saveCalendars saves one iCalendar file per student and one per group or lecture section, named subject_group.ics.
+func saveCalendars(schedule *university.Schedule, students []*university.Student, p string) error {
+	if err := os.MkdirAll(p, os.ModePerm); err != nil {
+		return err
+	}
+	for _, st := range students {
+		if err := ical.ForStudent(st).Save(st.Name, p); err != nil {
+			return err
+		}
+	}
 	for _, sub := range schedule.Subjects {
-		saveGroups(sub.Name, p, sub.Groups)
-		saveGroups(sub.Name, p, sub.Lectures)
+		for _, gs := range [][]*university.Group{sub.Lectures, sub.Groups} {
+			for _, g := range gs {
+				if err := ical.ForGroup(sub.Name, g).Save(sub.Name+"_"+g.Name, p); err != nil {
+					return err
+				}
+			}
+		}
 	}
 	return nil
 }
 
-func saveGroups(sn, p string, grs []*university.Group) error {
+// saveTimetables saves weekly timetables as .xlsx and .html files in students and teachers subdirectories.
+func saveTimetables(schedule *university.Schedule, students []*university.Student, p string) error {
+	var grs []*grid.Grid
+	for _, st := range students {
+		grs = append(grs, grid.ForStudent(st))
+	}
+	if err := saveGrids(grs, filepath.Join(p, "students")); err != nil {
+		return err
+	}
+	grs = nil
+	for _, t := range schedule.Teachers() {
+		grs = append(grs, grid.ForTeacher(schedule, t.Name))
+	}
+	return saveGrids(grs, filepath.Join(p, "teachers"))
+}
+
+func saveGrids(grs []*grid.Grid, p string) error {
+	if err := os.MkdirAll(p, os.ModePerm); err != nil {
+		return err
+	}
 	for _, g := range grs {
-		if err := xlsx.Write(sn, p, g.Name, g.Save()); err != nil {
+		if err := g.SaveXLSX(g.Title, p); err != nil {
+			return err
+		}
+		if err := g.SaveHTML(g.Title, p); err != nil {
 			return err
 		}
 	}
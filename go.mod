@@ -5,4 +5,7 @@ go 1.14
 require (
 	github.com/360EntSecGroup-Skylar/excelize v1.4.1
 	github.com/google/go-cmp v0.4.0
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/sys v0.22.0 // indirect
+	gopkg.in/yaml.v2 v2.3.0
 )
@@ -0,0 +1,158 @@
+// Package csv provides standard operations on files with .csv format.
+// The layout of data is the same as in .xlsx files, a file contains one sheet.
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrPathNotExists is returned when path to a file was not found.
+	ErrPathNotExists = errors.New("path to a file does not exists")
+	// ErrFileNotExists is returned when file was not found.
+	ErrFileNotExists = errors.New("file does not exists")
+	// ErrRows is returned when rows could not be read.
+	ErrRows = errors.New("could not read rows")
+)
+
+// Operation is a type of action which can be performed on a .csv file.
+// It is used in error messages.
+type Operation string
+
+const (
+	// ReadOp is a read operation.
+	ReadOp Operation = "read"
+	// WriteOp is a write operation.
+	WriteOp Operation = "write"
+)
+
+// Error represents an error struct returned by this package.
+type Error struct {
+	Op   Operation
+	File string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return string(e.Op) + " " + e.File + ": " + e.Err.Error()
+}
+
+// RowsError represents an error struct returned when rows could not be read.
+// Line - line of a file at which reading failed, 0 if it is unknown.
+// It matches ErrRows, the underlying error is returned by Unwrap.
+type RowsError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowsError) Error() string {
+	return ErrRows.Error() + ": " + e.Err.Error()
+}
+
+func (e *RowsError) Unwrap() error {
+	return e.Err
+}
+
+// Is checks if a target is ErrRows.
+func (e *RowsError) Is(target error) bool {
+	return target == ErrRows
+}
+
+// Format implements format.Format for .csv files.
+type Format struct{}
+
+// ReadSheet implements format.Format.
+func (Format) ReadSheet(n string, skip bool) (string, [][]string, error) {
+	return ReadSheet(n, skip)
+}
+
+// Write implements format.Format.
+func (Format) Write(n, p, s string, dd [][]string) error {
+	return Write(n, p, s, dd)
+}
+
+// Read retrieves data from file n.
+// If skip is set to true, it skips the first line.
+func Read(n string, skip bool) ([][]string, error) {
+	_, data, err := ReadSheet(n, skip)
+	return data, err
+}
+
+// ReadSheet retrieves data from file n.
+// The name of a sheet is the name of a file without extension.
+// If skip is set to true, it skips the first line.
+func ReadSheet(n string, skip bool) (string, [][]string, error) {
+	p, err := filepath.Abs(n)
+	if err != nil {
+		return "", nil, &Error{Op: ReadOp, File: n, Err: ErrPathNotExists}
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return "", nil, &Error{Op: ReadOp, File: n, Err: ErrFileNotExists}
+	}
+	defer f.Close()
+	rows, err := ReadFrom(f, skip)
+	if err != nil {
+		return "", nil, &Error{Op: ReadOp, File: n, Err: err}
+	}
+	return strings.TrimSuffix(filepath.Base(n), filepath.Ext(n)), rows, nil
+}
+
+// ReadFrom retrieves data from r.
+// If skip is set to true, it skips the first line.
+// It returns RowsError with the line at which data is malformed.
+func ReadFrom(r io.Reader, skip bool) ([][]string, error) {
+	cr := stdcsv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		re := &RowsError{Err: err}
+		var pe *stdcsv.ParseError
+		if errors.As(err, &pe) {
+			re.Line = pe.Line
+		}
+		return nil, re
+	}
+	if rows == nil {
+		rows = make([][]string, 0)
+	}
+	// Files exported from spreadsheets often start with a byte order mark
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+	// Skip heading
+	if skip && len(rows) > 0 {
+		rows = rows[1:]
+	}
+	return rows, nil
+}
+
+// Write creates a file with a given name in a given path and saves passed data in it.
+// A .csv file contains only one sheet, so data of sheet s is saved in a file named n_s.csv.
+// If the sheet has the same name as the file, the file is named n.csv.
+func Write(n, p, s string, dd [][]string) error {
+	rp, err := filepath.Abs(p)
+	if err != nil {
+		return &Error{Op: WriteOp, File: p, Err: ErrPathNotExists}
+	}
+	fn := n
+	if !strings.EqualFold(n, s) {
+		fn += "_" + s
+	}
+	fn = filepath.Join(rp, fn+".csv")
+	f, err := os.Create(fn)
+	if err != nil {
+		return &Error{Op: WriteOp, File: fn, Err: err}
+	}
+	w := stdcsv.NewWriter(f)
+	if err := w.WriteAll(dd); err != nil {
+		f.Close()
+		return &Error{Op: WriteOp, File: fn, Err: err}
+	}
+	return f.Close()
+}
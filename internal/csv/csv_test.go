@@ -0,0 +1,192 @@
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestError(t *testing.T) {
+	e := &Error{
+		Op:   ReadOp,
+		File: "file.csv",
+		Err:  ErrFileNotExists,
+	}
+	want := "read file.csv: file does not exists"
+	got := e.Error()
+	if got != want {
+		t.Errorf("Error() got = %v, want %v", got, want)
+	}
+}
+
+func TestReadSheet(t *testing.T) {
+	type args struct {
+		n    string
+		skip bool
+	}
+	tests := []struct {
+		name  string
+		args  args
+		sheet string
+		want  [][]string
+		err   error
+	}{
+		{
+			name: "Fails on non-existing file",
+			args: args{
+				n: "no_such_file.csv",
+			},
+			err: &Error{
+				Op:   ReadOp,
+				File: "no_such_file.csv",
+				Err:  ErrFileNotExists,
+			},
+		},
+		{
+			name: "Successfully reads data",
+			args: args{
+				n:    "../../test/data/csv/read.csv",
+				skip: true,
+			},
+			sheet: "read",
+			want: [][]string{
+				{"AA", "AA"},
+				{"BB", "BB"},
+				{"CC", "CC"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet, got, err := ReadSheet(tt.args.n, tt.args.skip)
+			if !tools.CompareErrors(err, tt.err) {
+				t.Errorf("ReadSheet() error = %v, err %v", err, tt.err)
+			}
+			if sheet != tt.sheet {
+				t.Errorf("ReadSheet() sheet = %v, want %v", sheet, tt.sheet)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadSheet() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	p := "./tmp"
+	type args struct {
+		n  string
+		p  string
+		s  string
+		dd [][]string
+	}
+	tests := []struct {
+		name string
+		args args
+		file string
+		want [][]string
+		err  error
+	}{
+		{
+			name: "Successfully creates a file",
+			args: args{
+				n: "new-file",
+				p: p,
+				s: "new-file",
+				dd: [][]string{
+					{"aaa", "a,a"},
+					{"bbb", "bbb"},
+				},
+			},
+			file: "new-file.csv",
+			want: [][]string{
+				{"aaa", "a,a"},
+				{"bbb", "bbb"},
+			},
+		},
+		{
+			name: "Adds sheet name to file name",
+			args: args{
+				n: "Math",
+				p: p,
+				s: "1a",
+				dd: [][]string{
+					{"aaa"},
+				},
+			},
+			file: "Math_1a.csv",
+			want: [][]string{
+				{"aaa"},
+			},
+		},
+	}
+	// Create tmp directory for test
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		os.Mkdir(p, os.ModePerm)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Write(tt.args.n, tt.args.p, tt.args.s, tt.args.dd)
+			if !tools.CompareErrors(err, tt.err) {
+				t.Errorf("Write() error = %v, err %v", err, tt.err)
+			}
+			got, _ := Read(fmt.Sprintf("%s/%s", tt.args.p, tt.file), false)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	os.RemoveAll(p)
+}
+
+func TestReadFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		skip bool
+		want [][]string
+		err  error
+	}{
+		{
+			name: "Successfully reads data without a heading",
+			in:   "\ufeffname,group\nAA,1\nBB\n",
+			skip: true,
+			want: [][]string{
+				{"AA", "1"},
+				{"BB"},
+			},
+		},
+		{
+			name: "Successfully reads empty data",
+			want: [][]string{},
+		},
+		{
+			name: "Fails on malformed data",
+			in:   "name,group\nAA,1\n\"BB,2\n",
+			err: &RowsError{
+				Line: 3,
+				Err:  &stdcsv.ParseError{StartLine: 3, Line: 3, Column: 7, Err: stdcsv.ErrQuote},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadFrom(strings.NewReader(tt.in), tt.skip)
+			if !tools.CompareErrors(err, tt.err) {
+				t.Errorf("ReadFrom() error = %v, err %v", err, tt.err)
+			}
+			if tt.err != nil && !errors.Is(err, ErrRows) {
+				t.Errorf("ReadFrom() error = %v, is not %v", err, ErrRows)
+			}
+			if !reflect.DeepEqual(got, tt.want) && tt.err == nil {
+				t.Errorf("ReadFrom() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,503 @@
+// Package document converts a schedule and enrolled students to a versioned document
+// which can be saved as JSON or YAML and loaded back.
+package document
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pbartkowicz/scheduler/internal/university"
+	"gopkg.in/yaml.v2"
+)
+
+// Version is the version of documents created by this package.
+const Version = 1
+
+const (
+	timeLayout = "15:04"
+	dateLayout = "2006-01-02"
+)
+
+var (
+	// ErrWrongVersion is returned when a document has a version which is not supported.
+	ErrWrongVersion = fmt.Errorf("incorrect document version, supported version: %d", Version)
+	// ErrWrongEncoding is returned when a passed encoding is not supported.
+	ErrWrongEncoding = errors.New("incorrect encoding, available encodings: json, yaml")
+	// ErrNoMeetings is returned when a group has no meetings.
+	ErrNoMeetings = errors.New("group has no meetings")
+	// ErrUnknownStudent is returned when a group contains a student who does not exist in a document.
+	ErrUnknownStudent = errors.New("unknown student")
+)
+
+// Error represents an error struct returned when a document cannot be converted to a schedule.
+// Path - location of an incorrect value in a document, e.g. subjects.Math.groups.1.
+type Error struct {
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("incorrect document [%s]: %s", e.Path, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Document represents a whole schedule with enrolled students.
+// Students are referenced by name in groups and groups are referenced by name in students,
+// so a document contains no pointer cycles.
+type Document struct {
+	Version  int        `json:"version" yaml:"version"`
+	Subjects []*Subject `json:"subjects" yaml:"subjects"`
+	Students []*Student `json:"students" yaml:"students"`
+	History  []*Event   `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+// Subject represents one subject with its lecture sections and groups.
+type Subject struct {
+	Name     string   `json:"name" yaml:"name"`
+	Lectures []*Group `json:"lectures,omitempty" yaml:"lectures,omitempty"`
+	Groups   []*Group `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// Group represents a group or a lecture section.
+// Meetings - the first meeting is the group itself, every next one is a subgroup.
+// Students and PriorityStudents contain names of enrolled students, Waitlist contains names of waitlisted students in order.
+type Group struct {
+	Name             string     `json:"name" yaml:"name"`
+	Type             string     `json:"type" yaml:"type"`
+	Capacity         int        `json:"capacity" yaml:"capacity"`
+	Meetings         []*Meeting `json:"meetings" yaml:"meetings"`
+	Students         []string   `json:"students,omitempty" yaml:"students,omitempty"`
+	PriorityStudents []string   `json:"priorityStudents,omitempty" yaml:"priorityStudents,omitempty"`
+	Waitlist         []string   `json:"waitlist,omitempty" yaml:"waitlist,omitempty"`
+}
+
+// Meeting represents a single weekly meeting of a group.
+// Weekday is a name of a day, e.g. Monday, times are formatted as 15:04 and dates as 2006-01-02.
+type Meeting struct {
+	Teacher   string `json:"teacher" yaml:"teacher"`
+	Weekday   string `json:"weekday" yaml:"weekday"`
+	StartTime string `json:"startTime" yaml:"startTime"`
+	EndTime   string `json:"endTime" yaml:"endTime"`
+	Place     string `json:"place" yaml:"place"`
+	StartDate string `json:"startDate,omitempty" yaml:"startDate,omitempty"`
+	EndDate   string `json:"endDate,omitempty" yaml:"endDate,omitempty"`
+	Frequency int    `json:"frequency" yaml:"frequency"`
+}
+
+// Student represents a student with their preferences and results of enrollment.
+// Subjects - subjects in which a student is enrolled.
+// Groups and Lectures - names of final groups and lecture sections by subject name.
+type Student struct {
+	Name        string             `json:"name" yaml:"name"`
+	Priority    bool               `json:"priority" yaml:"priority"`
+	Subjects    []string           `json:"subjects" yaml:"subjects"`
+	Preferences []*Preference      `json:"preferences,omitempty" yaml:"preferences,omitempty"`
+	Happiness   map[string]float64 `json:"happiness,omitempty" yaml:"happiness,omitempty"`
+	Groups      map[string]string  `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Lectures    map[string]string  `json:"lectures,omitempty" yaml:"lectures,omitempty"`
+}
+
+// Event represents a change of a student's group after enrollment, see university.Event.
+type Event struct {
+	Time    time.Time `json:"time" yaml:"time"`
+	Type    string    `json:"type" yaml:"type"`
+	Student string    `json:"student" yaml:"student"`
+	Subject string    `json:"subject" yaml:"subject"`
+	Class   string    `json:"class" yaml:"class"`
+	From    string    `json:"from,omitempty" yaml:"from,omitempty"`
+	To      string    `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Preference represents a priority which a student set to a group.
+// Points - points which a student bid on a group, set only in the bidding mode.
+type Preference struct {
+	Subject  string `json:"subject" yaml:"subject"`
+	Group    string `json:"group" yaml:"group"`
+	Priority int    `json:"priority" yaml:"priority"`
+	Points   int    `json:"points,omitempty" yaml:"points,omitempty"`
+}
+
+// New creates a document from a schedule and students.
+func New(s *university.Schedule, students []*university.Student) *Document {
+	d := &Document{Version: Version}
+	for _, sub := range s.Subjects {
+		d.Subjects = append(d.Subjects, &Subject{
+			Name:     sub.Name,
+			Lectures: newGroups(sub.Lectures),
+			Groups:   newGroups(sub.Groups),
+		})
+	}
+	for _, st := range students {
+		d.Students = append(d.Students, newStudent(s, st))
+	}
+	d.History = Events(s.History)
+	return d
+}
+
+// Events converts changes of students' groups to events of a document.
+func Events(es []*university.Event) (res []*Event) {
+	for _, e := range es {
+		res = append(res, &Event{
+			Time:    e.Time,
+			Type:    string(e.Type),
+			Student: e.Student,
+			Subject: e.Subject,
+			Class:   string(e.Class),
+			From:    e.From,
+			To:      e.To,
+		})
+	}
+	return
+}
+
+func newGroups(grs []*university.Group) (res []*Group) {
+	for _, g := range grs {
+		ng := &Group{
+			Name:             g.Name,
+			Type:             string(g.Type),
+			Capacity:         g.Capacity,
+			Students:         names(g.Students),
+			PriorityStudents: names(g.PriorityStudents),
+			Waitlist:         names(g.Waitlist),
+		}
+		for _, m := range g.Meetings() {
+			ng.Meetings = append(ng.Meetings, &Meeting{
+				Teacher:   m.Teacher,
+				Weekday:   m.Weekday.String(),
+				StartTime: m.StartTime.Format(timeLayout),
+				EndTime:   m.EndTime.Format(timeLayout),
+				Place:     m.Place,
+				StartDate: formatDate(m.StartDate),
+				EndDate:   formatDate(m.EndDate),
+				Frequency: m.Frequency,
+			})
+		}
+		res = append(res, ng)
+	}
+	return
+}
+
+func names(sts []*university.Student) (res []string) {
+	for _, st := range sts {
+		res = append(res, st.Name)
+	}
+	return
+}
+
+func newStudent(s *university.Schedule, st *university.Student) *Student {
+	res := &Student{
+		Name:      st.Name,
+		Priority:  st.Priority,
+		Subjects:  []string{},
+		Happiness: st.Happiness,
+		Groups:    groupNames(st.FinalGroups),
+		Lectures:  groupNames(st.FinalLectures),
+	}
+	for _, sub := range s.Subjects {
+		if st.Enrolled(sub.Name) {
+			res.Subjects = append(res.Subjects, sub.Name)
+		}
+	}
+	for k, v := range st.Preferences {
+		res.Preferences = append(res.Preferences, &Preference{Subject: k.Subject, Group: k.Group, Priority: v, Points: st.Bids[k]})
+	}
+	sort.Slice(res.Preferences, func(i, j int) bool {
+		a, b := res.Preferences[i], res.Preferences[j]
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.Group < b.Group
+	})
+	return res
+}
+
+func groupNames(grs map[string]*university.Group) map[string]string {
+	res := make(map[string]string)
+	for k, g := range grs {
+		if g != nil {
+			res[k] = g.Name
+		}
+	}
+	return res
+}
+
+func formatDate(d time.Time) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(dateLayout)
+}
+
+// Schedule creates a schedule and students from a document.
+// Students in groups and groups in students are linked back by name.
+// It returns Error when a document contains an incorrect value or reference.
+func (d *Document) Schedule() (*university.Schedule, []*university.Student, error) {
+	if d.Version != Version {
+		return nil, nil, &Error{Path: "version", Err: ErrWrongVersion}
+	}
+	students := make(map[string]*university.Student)
+	var sts []*university.Student
+	for _, ds := range d.Students {
+		st := &university.Student{
+			Name:          ds.Name,
+			Priority:      ds.Priority,
+			Subjects:      append([]string{}, ds.Subjects...),
+			Preferences:   make(map[university.SubjectGroup]int),
+			Happiness:     make(map[string]float64),
+			FinalGroups:   make(map[string]*university.Group),
+			FinalLectures: make(map[string]*university.Group),
+		}
+		for _, p := range ds.Preferences {
+			st.Preferences[university.SubjectGroup{Subject: p.Subject, Group: p.Group}] = p.Priority
+			if p.Points > 0 {
+				if st.Bids == nil {
+					st.Bids = make(map[university.SubjectGroup]int)
+				}
+				st.Bids[university.SubjectGroup{Subject: p.Subject, Group: p.Group}] = p.Points
+			}
+		}
+		for k, v := range ds.Happiness {
+			st.Happiness[k] = v
+		}
+		students[st.Name] = st
+		sts = append(sts, st)
+	}
+
+	s := &university.Schedule{}
+	for _, ds := range d.Subjects {
+		p := "subjects." + ds.Name
+		ls, err := schedulingGroups(ds.Lectures, students, p+".lectures")
+		if err != nil {
+			return nil, nil, err
+		}
+		grs, err := schedulingGroups(ds.Groups, students, p+".groups")
+		if err != nil {
+			return nil, nil, err
+		}
+		s.Subjects = append(s.Subjects, &university.Subject{
+			Name:     ds.Name,
+			Lectures: ls,
+			Groups:   grs,
+		})
+	}
+	for _, e := range d.History {
+		s.History = append(s.History, &university.Event{
+			Time:    e.Time,
+			Type:    university.EventType(e.Type),
+			Student: e.Student,
+			Subject: e.Subject,
+			Class:   university.ClassType(e.Class),
+			From:    e.From,
+			To:      e.To,
+		})
+	}
+
+	for _, ds := range d.Students {
+		st := students[ds.Name]
+		p := "students." + ds.Name
+		for k, n := range ds.Groups {
+			sub := s.GetSubject(k)
+			if sub == nil {
+				return nil, nil, &Error{Path: p + ".groups", Err: fmt.Errorf("%w: %s", university.ErrUnknownSubject, k)}
+			}
+			if st.FinalGroups[k] = sub.GetGroup(n); st.FinalGroups[k] == nil {
+				return nil, nil, &Error{Path: p + ".groups", Err: fmt.Errorf("%w: %s %s", university.ErrUnknownGroup, k, n)}
+			}
+		}
+		for k, n := range ds.Lectures {
+			sub := s.GetSubject(k)
+			if sub == nil {
+				return nil, nil, &Error{Path: p + ".lectures", Err: fmt.Errorf("%w: %s", university.ErrUnknownSubject, k)}
+			}
+			if st.FinalLectures[k] = sub.GetLecture(n); st.FinalLectures[k] == nil {
+				return nil, nil, &Error{Path: p + ".lectures", Err: fmt.Errorf("%w: %s %s", university.ErrUnknownGroup, k, n)}
+			}
+		}
+	}
+	return s, sts, nil
+}
+
+// schedulingGroups creates groups of a subject, the first meeting of a group becomes the group and the rest its subgroups.
+func schedulingGroups(dgs []*Group, students map[string]*university.Student, p string) (res []*university.Group, err error) {
+	for _, dg := range dgs {
+		gp := p + "." + dg.Name
+		if len(dg.Meetings) == 0 {
+			return nil, &Error{Path: gp + ".meetings", Err: ErrNoMeetings}
+		}
+		var g *university.Group
+		for i, dm := range dg.Meetings {
+			m, err := schedulingMeeting(dg, dm)
+			if err != nil {
+				return nil, &Error{Path: fmt.Sprintf("%s.meetings.%d", gp, i), Err: err}
+			}
+			if g == nil {
+				g = m
+				continue
+			}
+			g.SubGroups = append(g.SubGroups, m)
+		}
+		if g.Students, err = lookup(dg.Students, students); err != nil {
+			return nil, &Error{Path: gp + ".students", Err: err}
+		}
+		if g.PriorityStudents, err = lookup(dg.PriorityStudents, students); err != nil {
+			return nil, &Error{Path: gp + ".priorityStudents", Err: err}
+		}
+		if g.Waitlist, err = lookup(dg.Waitlist, students); err != nil {
+			return nil, &Error{Path: gp + ".waitlist", Err: err}
+		}
+		res = append(res, g)
+	}
+	return
+}
+
+func schedulingMeeting(dg *Group, dm *Meeting) (*university.Group, error) {
+	t := university.ClassType(dg.Type)
+	if t != university.Class && t != university.Lecture && t != university.Laboratory {
+		return nil, university.ErrWrongClassType
+	}
+	w, err := parseWeekday(dm.Weekday)
+	if err != nil {
+		return nil, err
+	}
+	st, err := time.Parse(timeLayout, dm.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	et, err := time.Parse(timeLayout, dm.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := parseDate(dm.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	ed, err := parseDate(dm.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	return &university.Group{
+		Type:      t,
+		Teacher:   dm.Teacher,
+		Weekday:   w,
+		StartTime: st,
+		EndTime:   et,
+		Place:     dm.Place,
+		StartDate: sd,
+		EndDate:   ed,
+		Frequency: dm.Frequency,
+		Name:      dg.Name,
+		Capacity:  dg.Capacity,
+	}, nil
+}
+
+func parseWeekday(n string) (time.Weekday, error) {
+	for w := time.Sunday; w <= time.Saturday; w++ {
+		if w.String() == n {
+			return w, nil
+		}
+	}
+	return 0, university.ErrWrongWeekday
+}
+
+func parseDate(d string) (time.Time, error) {
+	if d == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(dateLayout, d)
+}
+
+func lookup(ns []string, students map[string]*university.Student) (res []*university.Student, err error) {
+	for _, n := range ns {
+		st := students[n]
+		if st == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownStudent, n)
+		}
+		res = append(res, st)
+	}
+	return
+}
+
+// Encode writes a document to w, available encodings: json, yaml.
+func (d *Document) Encode(w io.Writer, enc string) error {
+	switch enc {
+	case "json":
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(d)
+	case "yaml":
+		return yaml.NewEncoder(w).Encode(d)
+	}
+	return ErrWrongEncoding
+}
+
+// Decode reads a document from r, available encodings: json, yaml.
+func Decode(r io.Reader, enc string) (*Document, error) {
+	d := &Document{}
+	switch enc {
+	case "json":
+		if err := json.NewDecoder(r).Decode(d); err != nil {
+			return nil, err
+		}
+	case "yaml":
+		if err := yaml.NewDecoder(r).Decode(d); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrWrongEncoding
+	}
+	return d, nil
+}
+
+// Save writes a document to a file, the encoding is chosen by the file extension: .json, .yaml or .yml.
+func (d *Document) Save(n string) error {
+	enc, err := encoding(n)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(n)
+	if err != nil {
+		return err
+	}
+	if err := d.Encode(f, enc); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads a document from a file, the encoding is chosen by the file extension: .json, .yaml or .yml.
+func Load(n string) (*Document, error) {
+	enc, err := encoding(n)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(n)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f, enc)
+}
+
+func encoding(n string) (string, error) {
+	switch strings.ToLower(filepath.Ext(n)) {
+	case ".json":
+		return "json", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	}
+	return "", fmt.Errorf("%s: %w", n, ErrWrongEncoding)
+}
@@ -0,0 +1,233 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/university"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestDocument_RoundTrip(t *testing.T) {
+	for _, enc := range []string{"json", "yaml"} {
+		t.Run(enc, func(t *testing.T) {
+			a := &university.Student{
+				Name:          "a",
+				Preferences:   map[university.SubjectGroup]int{{Subject: "Math", Group: "1"}: 1, {Subject: "Math", Group: "2"}: 2},
+				Bids:          map[university.SubjectGroup]int{{Subject: "Math", Group: "1"}: 30, {Subject: "Math", Group: "2"}: 10},
+				Happiness:     map[string]float64{"Math": 100},
+				FinalGroups:   make(map[string]*university.Group),
+				FinalLectures: make(map[string]*university.Group),
+			}
+			b := &university.Student{
+				Name:          "b",
+				Priority:      true,
+				Subjects:      []string{"Math"},
+				Preferences:   map[university.SubjectGroup]int{},
+				Happiness:     map[string]float64{"Math": 0},
+				FinalGroups:   make(map[string]*university.Group),
+				FinalLectures: make(map[string]*university.Group),
+			}
+			l := &university.Group{
+				Type:      university.Lecture,
+				Teacher:   "T",
+				Weekday:   time.Monday,
+				StartTime: time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC),
+				Place:     "A1",
+				StartDate: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC),
+				Frequency: 1,
+				Name:      "Lecture",
+				Capacity:  10,
+				Students:  []*university.Student{a},
+			}
+			g := &university.Group{
+				Type:             university.Class,
+				Teacher:          "T",
+				Weekday:          time.Tuesday,
+				StartTime:        time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+				EndTime:          time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC),
+				Place:            "B2",
+				Frequency:        2,
+				Name:             "1",
+				Capacity:         2,
+				Students:         []*university.Student{a},
+				PriorityStudents: []*university.Student{b},
+				SubGroups: []*university.Group{
+					{
+						Type:      university.Class,
+						Teacher:   "U",
+						Weekday:   time.Thursday,
+						StartTime: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+						EndTime:   time.Date(0, 1, 1, 13, 30, 0, 0, time.UTC),
+						Place:     "B3",
+						Frequency: 1,
+						Name:      "1",
+						Capacity:  2,
+					},
+				},
+			}
+			g2 := &university.Group{
+				Type:      university.Class,
+				Weekday:   time.Friday,
+				StartTime: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC),
+				Frequency: 1,
+				Name:      "2",
+				Capacity:  1,
+				Waitlist:  []*university.Student{a},
+			}
+			a.FinalGroups["Math"] = g
+			a.FinalLectures["Math"] = l
+			b.FinalGroups["Math"] = g
+			s := &university.Schedule{
+				Subjects: []*university.Subject{
+					{
+						Name:     "Math",
+						Lectures: []*university.Group{l},
+						Groups:   []*university.Group{g, g2},
+					},
+				},
+				History: []*university.Event{
+					{Time: time.Date(2020, 3, 5, 10, 0, 0, 0, time.UTC), Type: university.EventPromoted, Student: "a", Subject: "Math", Class: university.Class, From: "2", To: "1"},
+				},
+			}
+			students := []*university.Student{a, b}
+			var buf bytes.Buffer
+			if err := New(s, students).Encode(&buf, enc); err != nil {
+				t.Fatalf("Document.Encode() error = %v", err)
+			}
+			d, err := Decode(&buf, enc)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			gotS, gotSts, err := d.Schedule()
+			if err != nil {
+				t.Fatalf("Document.Schedule() error = %v", err)
+			}
+			// Students enrolled by preferences receive an explicit list of subjects
+			students[0].Subjects = []string{"Math"}
+			if !cmp.Equal(gotS, s) {
+				t.Errorf("Document.Schedule() schedule diff = %s", cmp.Diff(gotS, s))
+			}
+			if !cmp.Equal(gotSts, students) {
+				t.Errorf("Document.Schedule() students diff = %s", cmp.Diff(gotSts, students))
+			}
+			if gotSts[0].FinalGroups["Math"] != gotS.Subjects[0].Groups[0] {
+				t.Errorf("Document.Schedule() final group is not linked to a group of the schedule")
+			}
+			if gotS.Subjects[0].Groups[0].Students[0] != gotSts[0] {
+				t.Errorf("Document.Schedule() group student is not linked to a student")
+			}
+		})
+	}
+}
+
+func TestDocument_Schedule(t *testing.T) {
+	tests := []struct {
+		name string
+		d    *Document
+		err  error
+	}{
+		{
+			name: "Fails on unsupported version",
+			d:    &Document{Version: 2},
+			err:  &Error{Path: "version", Err: ErrWrongVersion},
+		},
+		{
+			name: "Fails on incorrect weekday",
+			d: &Document{
+				Version: Version,
+				Subjects: []*Subject{
+					{
+						Name: "Math",
+						Groups: []*Group{
+							{Name: "2", Type: "Class", Capacity: 1, Meetings: []*Meeting{{Weekday: "Someday", StartTime: "10:00", EndTime: "11:30", Frequency: 1}}},
+						},
+					},
+				},
+			},
+			err: &Error{Path: "subjects.Math.groups.2.meetings.0", Err: university.ErrWrongWeekday},
+		},
+		{
+			name: "Fails on group without meetings",
+			d: &Document{
+				Version: Version,
+				Subjects: []*Subject{
+					{
+						Name:   "Math",
+						Groups: []*Group{{Name: "2", Type: "Class", Capacity: 1}},
+					},
+				},
+			},
+			err: &Error{Path: "subjects.Math.groups.2.meetings", Err: ErrNoMeetings},
+		},
+		{
+			name: "Fails on unknown student",
+			d: &Document{
+				Version: Version,
+				Subjects: []*Subject{
+					{
+						Name: "Math",
+						Lectures: []*Group{
+							{Name: "Lecture", Type: "Lecture", Capacity: 10, Meetings: []*Meeting{{Weekday: "Monday", StartTime: "08:00", EndTime: "09:30", Frequency: 1}}, Students: []string{"c"}},
+						},
+					},
+				},
+				Students: []*Student{{Name: "a", Subjects: []string{"Math"}}},
+			},
+			err: &Error{Path: "subjects.Math.lectures.Lecture.students", Err: fmt.Errorf("%w: c", ErrUnknownStudent)},
+		},
+		{
+			name: "Fails on unknown waitlisted student",
+			d: &Document{
+				Version: Version,
+				Subjects: []*Subject{
+					{
+						Name: "Math",
+						Groups: []*Group{
+							{Name: "2", Type: "Class", Capacity: 1, Meetings: []*Meeting{{Weekday: "Friday", StartTime: "10:00", EndTime: "11:30", Frequency: 1}}, Waitlist: []string{"c"}},
+						},
+					},
+				},
+				Students: []*Student{{Name: "a", Subjects: []string{"Math"}}},
+			},
+			err: &Error{Path: "subjects.Math.groups.2.waitlist", Err: fmt.Errorf("%w: c", ErrUnknownStudent)},
+		},
+		{
+			name: "Fails on unknown group of a student",
+			d: &Document{
+				Version: Version,
+				Subjects: []*Subject{
+					{
+						Name: "Math",
+						Groups: []*Group{
+							{Name: "1", Type: "Class", Capacity: 1, Meetings: []*Meeting{{Weekday: "Tuesday", StartTime: "10:00", EndTime: "11:30", Frequency: 1}}},
+						},
+					},
+				},
+				Students: []*Student{{Name: "a", Subjects: []string{"Math"}, Groups: map[string]string{"Math": "3"}}},
+			},
+			err: &Error{Path: "students.a.groups", Err: fmt.Errorf("%w: Math 3", university.ErrUnknownGroup)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.d.Schedule()
+			if !tools.CompareErrors(err, tt.err) {
+				t.Errorf("Document.Schedule() error = %v, err %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	_, err := Decode(&bytes.Buffer{}, "xml")
+	if err != ErrWrongEncoding {
+		t.Errorf("Decode() error = %v, err %v", err, ErrWrongEncoding)
+	}
+}
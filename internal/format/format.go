@@ -0,0 +1,55 @@
+// Package format provides a common interface for files with tabular data.
+package format
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/pbartkowicz/scheduler/internal/csv"
+	"github.com/pbartkowicz/scheduler/internal/xlsx"
+)
+
+// ErrWrongFormat is returned when a format of a file is not supported.
+var ErrWrongFormat = errors.New("incorrect format, available formats: xlsx, csv")
+
+// Format reads and writes files with tabular data.
+// ReadSheet retrieves data from the first sheet of file n together with the name of the sheet,
+// if skip is set to true, it skips the first line.
+// Write creates a file with a given name in a given path and saves passed data in sheet s.
+type Format interface {
+	ReadSheet(n string, skip bool) (string, [][]string, error)
+	Write(n, p, s string, dd [][]string) error
+}
+
+var formats = map[string]Format{
+	"xlsx": xlsx.Format{},
+	"csv":  csv.Format{},
+}
+
+// New returns a format with a passed name.
+// It returns ErrWrongFormat when the name is incorrect.
+func New(n string) (Format, error) {
+	f, ok := formats[strings.ToLower(n)]
+	if !ok {
+		return nil, ErrWrongFormat
+	}
+	return f, nil
+}
+
+// ForFile returns a format based on the extension of file n.
+// It returns ErrWrongFormat when the extension is not supported.
+func ForFile(n string) (Format, error) {
+	return New(strings.TrimPrefix(filepath.Ext(n), "."))
+}
+
+// Read retrieves data from file n using a format based on its extension.
+// If skip is set to true, it skips the first line.
+func Read(n string, skip bool) ([][]string, error) {
+	f, err := ForFile(n)
+	if err != nil {
+		return nil, err
+	}
+	_, data, err := f.ReadSheet(n, skip)
+	return data, err
+}
@@ -0,0 +1,64 @@
+package format
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/csv"
+	"github.com/pbartkowicz/scheduler/internal/xlsx"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestForFile(t *testing.T) {
+	tests := []struct {
+		name string
+		n    string
+		want Format
+		err  error
+	}{
+		{
+			name: "Returns xlsx format",
+			n:    "groups.xlsx",
+			want: xlsx.Format{},
+		},
+		{
+			name: "Returns csv format",
+			n:    "students/aaa.CSV",
+			want: csv.Format{},
+		},
+		{
+			name: "Fails on unsupported extension",
+			n:    "groups.ods",
+			err:  ErrWrongFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ForFile(tt.n)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("ForFile() error = %v, err %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ForFile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	want := [][]string{
+		{"AA", "AA"},
+		{"BB", "BB"},
+		{"CC", "CC"},
+	}
+	for _, n := range []string{"../../test/data/xlsx/read.xlsx", "../../test/data/csv/read.csv"} {
+		got, err := Read(n, true)
+		if err != nil {
+			t.Errorf("Read() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Read() got = %v, want %v", got, want)
+		}
+	}
+}
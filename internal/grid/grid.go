@@ -0,0 +1,227 @@
+// Package grid lays out meetings of groups on a weekly Monday-Friday timetable
+// and renders it as a .xlsx sheet or a standalone HTML page.
+package grid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+// slotMinutes is the length of one row of a grid.
+const slotMinutes = 15
+
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// Entry represents one meeting shown on a grid.
+type Entry struct {
+	Subject string
+	Group   *university.Group
+	Meeting *university.Group
+}
+
+// Lines returns the text of an entry: subject, class type with group name, place and teacher.
+func (e *Entry) Lines() []string {
+	res := []string{e.Subject, fmt.Sprintf("%s %s", e.Group.Type, e.Group.Name)}
+	if e.Meeting.Place != "" {
+		res = append(res, e.Meeting.Place)
+	}
+	if e.Meeting.Teacher != "" {
+		res = append(res, e.Meeting.Teacher)
+	}
+	return res
+}
+
+// Grid represents a weekly timetable of a student or a teacher.
+type Grid struct {
+	Title   string
+	Entries []*Entry
+}
+
+// ForStudent creates a grid with final lecture sections and groups of a student.
+func ForStudent(st *university.Student) *Grid {
+	g := &Grid{Title: st.Name}
+	for _, fgs := range []map[string]*university.Group{st.FinalLectures, st.FinalGroups} {
+		for sub, fg := range fgs {
+			if fg != nil {
+				g.Add(sub, fg)
+			}
+		}
+	}
+	return g
+}
+
+// ForTeacher creates a grid with all meetings of a schedule which are taught by teacher t.
+func ForTeacher(s *university.Schedule, t string) *Grid {
+	g := &Grid{Title: t}
+	for _, sub := range s.Subjects {
+		for _, gs := range [][]*university.Group{sub.Lectures, sub.Groups} {
+			for _, gr := range gs {
+				for _, m := range gr.Meetings() {
+					if m.Teacher == t {
+						g.Entries = append(g.Entries, &Entry{Subject: sub.Name, Group: gr, Meeting: m})
+					}
+				}
+			}
+		}
+	}
+	return g
+}
+
+// Add adds all meetings of a group of subject sub to a grid.
+func (g *Grid) Add(sub string, gr *university.Group) {
+	for _, m := range gr.Meetings() {
+		g.Entries = append(g.Entries, &Entry{Subject: sub, Group: gr, Meeting: m})
+	}
+}
+
+// Cell represents an entry placed on a grid.
+// Row and Rows - the first time slot of an entry and the number of slots which it spans.
+// Column - index of a column, every weekday has as many columns as the number of its overlapping meetings.
+type Cell struct {
+	Entry  *Entry
+	Row    int
+	Rows   int
+	Column int
+}
+
+// Layout represents a grid split into time slots and columns.
+// Start - minute of a day of the first slot.
+// Days - number of columns of every weekday, from Monday to Friday.
+type Layout struct {
+	Start int
+	Slots int
+	Days  []int
+	Cells []*Cell
+}
+
+// Columns returns the total number of columns of all weekdays.
+func (l *Layout) Columns() (res int) {
+	for _, d := range l.Days {
+		res += d
+	}
+	return
+}
+
+// SlotLabel returns the start time of slot i, e.g. 09:30.
+func (l *Layout) SlotLabel(i int) string {
+	m := l.Start + i*slotMinutes
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
+
+// Layout places entries of a grid in time slots.
+// Meetings which overlap on the same weekday, e.g. held every other week, are placed in separate columns.
+// Meetings held on weekends are skipped.
+func (g *Grid) Layout() *Layout {
+	l := &Layout{Days: make([]int, len(weekdays))}
+	var es []*Entry
+	first, last := -1, -1
+	for _, e := range g.Entries {
+		if dayIndex(e.Meeting.Weekday) < 0 {
+			continue
+		}
+		es = append(es, e)
+		s, en := start(e.Meeting), end(e.Meeting)
+		if first < 0 || s < first {
+			first = s
+		}
+		if en > last {
+			last = en
+		}
+	}
+	if len(es) == 0 {
+		return l
+	}
+	l.Start = first / slotMinutes * slotMinutes
+	l.Slots = (last - l.Start + slotMinutes - 1) / slotMinutes
+	sort.SliceStable(es, func(i, j int) bool {
+		a, b := es[i].Meeting, es[j].Meeting
+		if a.Weekday != b.Weekday {
+			return a.Weekday < b.Weekday
+		}
+		if start(a) != start(b) {
+			return start(a) < start(b)
+		}
+		if es[i].Subject != es[j].Subject {
+			return es[i].Subject < es[j].Subject
+		}
+		return es[i].Group.Name < es[j].Group.Name
+	})
+
+	var cells [][]*Cell
+	for d := range weekdays {
+		// ends contains the last occupied slot of every column of a weekday
+		var ends []int
+		var dcs []*Cell
+		for _, e := range es {
+			if dayIndex(e.Meeting.Weekday) != d {
+				continue
+			}
+			r := (start(e.Meeting) - l.Start) / slotMinutes
+			rs := (end(e.Meeting) - l.Start + slotMinutes - 1) / slotMinutes
+			if rs <= r {
+				rs = r + 1
+			}
+			c := &Cell{Entry: e, Row: r, Rows: rs - r}
+			for c.Column = 0; c.Column < len(ends) && ends[c.Column] > r; c.Column++ {
+			}
+			if c.Column == len(ends) {
+				ends = append(ends, rs)
+			} else {
+				ends[c.Column] = rs
+			}
+			dcs = append(dcs, c)
+		}
+		l.Days[d] = len(ends)
+		if l.Days[d] == 0 {
+			l.Days[d] = 1
+		}
+		cells = append(cells, dcs)
+	}
+	var offset int
+	for d, dcs := range cells {
+		for _, c := range dcs {
+			c.Column += offset
+			l.Cells = append(l.Cells, c)
+		}
+		offset += l.Days[d]
+	}
+	return l
+}
+
+func dayIndex(w time.Weekday) int {
+	for i, d := range weekdays {
+		if d == w {
+			return i
+		}
+	}
+	return -1
+}
+
+func start(m *university.Group) int {
+	return m.StartTime.Hour()*60 + m.StartTime.Minute()
+}
+
+// end returns minute of a day on which a meeting ends, meetings without a valid end time last one slot.
+func end(m *university.Group) int {
+	e := m.EndTime.Hour()*60 + m.EndTime.Minute()
+	if e <= start(m) {
+		return start(m) + slotMinutes
+	}
+	return e
+}
+
+// sheetName returns a sanitized name of a grid which can be used as a sheet name.
+func sheetName(t string) string {
+	s := strings.NewReplacer(":", " ", "\\", " ", "/", " ", "?", " ", "*", " ", "[", " ", "]", " ").Replace(t)
+	if r := []rune(s); len(r) > 31 {
+		s = string(r[:31])
+	}
+	if s == "" {
+		return "Timetable"
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package grid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+func clock(h, m int) time.Time {
+	return time.Date(0, 1, 1, h, m, 0, 0, time.UTC)
+}
+
+// newTestGrid creates the grid which is rendered by layout, xlsx and HTML tests:
+// two overlapping groups on Monday, a short laboratory on Wednesday and a group on Saturday which is not shown.
+func newTestGrid() (*Grid, []*university.Group) {
+	grs := []*university.Group{
+		{Name: "1", Type: university.Class, Weekday: time.Monday, StartTime: clock(8, 0), EndTime: clock(9, 30), Teacher: "T"},
+		{Name: "2", Type: university.Class, Weekday: time.Monday, StartTime: clock(9, 0), EndTime: clock(10, 0), Teacher: "T"},
+		{Name: "3", Type: university.Laboratory, Weekday: time.Wednesday, StartTime: clock(9, 30), EndTime: clock(9, 50), Place: "B-2"},
+		{Name: "4", Type: university.Class, Weekday: time.Saturday, StartTime: clock(9, 30), EndTime: clock(11, 0)},
+	}
+	g := &Grid{Title: "a"}
+	for _, gr := range grs {
+		g.Add("Math", gr)
+	}
+	return g, grs
+}
+
+func TestGrid_Layout(t *testing.T) {
+	g, grs := newTestGrid()
+	l := g.Layout()
+	want := &Layout{
+		Start: 8 * 60,
+		Slots: 8,
+		Days:  []int{2, 1, 1, 1, 1},
+		Cells: []*Cell{
+			{Entry: g.Entries[0], Row: 0, Rows: 6, Column: 0},
+			{Entry: g.Entries[1], Row: 4, Rows: 4, Column: 1},
+			{Entry: g.Entries[2], Row: 6, Rows: 2, Column: 3},
+		},
+	}
+	if !cmp.Equal(l, want) {
+		t.Errorf("Grid.Layout() diff = %s", cmp.Diff(l, want))
+	}
+	if l.SlotLabel(7) != "09:45" {
+		t.Errorf("Layout.SlotLabel() = %v, want %v", l.SlotLabel(7), "09:45")
+	}
+	if l.Cells[1].Entry.Group != grs[1] {
+		t.Errorf("Grid.Layout() cell is not linked to a group")
+	}
+}
+
+func TestForTeacher(t *testing.T) {
+	grs := []*university.Group{
+		{Name: "1", Type: university.Class, Weekday: time.Monday, StartTime: clock(8, 0), EndTime: clock(9, 30), Teacher: "T"},
+		{Name: "2", Type: university.Class, Weekday: time.Monday, StartTime: clock(9, 0), EndTime: clock(10, 0), Teacher: "T"},
+		{Name: "3", Type: university.Laboratory, Weekday: time.Wednesday, StartTime: clock(9, 30), EndTime: clock(9, 50)},
+	}
+	s := &university.Schedule{
+		Subjects: []*university.Subject{
+			{Name: "Math", Groups: grs},
+		},
+	}
+	g := ForTeacher(s, "T")
+	if len(g.Entries) != 2 || g.Entries[0].Group != grs[0] || g.Entries[1].Group != grs[1] {
+		t.Errorf("ForTeacher() entries = %v, want groups 1 and 2", g.Entries)
+	}
+}
+
+func TestEntry_Lines(t *testing.T) {
+	g := &Grid{Title: "a"}
+	g.Add("Math", &university.Group{Name: "3", Type: university.Laboratory, Weekday: time.Wednesday, StartTime: clock(9, 30), EndTime: clock(9, 50), Place: "B-2"})
+	want := []string{"Math", "Laboratory 3", "B-2"}
+	if got := g.Entries[0].Lines(); !cmp.Equal(got, want) {
+		t.Errorf("Entry.Lines() = %v, want %v", got, want)
+	}
+}
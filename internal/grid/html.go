@@ -0,0 +1,97 @@
+package grid
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var page = template.Must(template.New("grid").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; table-layout: fixed; }
+th, td { border: 1px solid #d0d0d0; padding: 2px 4px; font-size: 12px; }
+th { background: #f2f2f2; }
+td.time { color: #606060; white-space: nowrap; vertical-align: top; }
+td.entry { background: #ddebf7; border-color: #5b9bd5; text-align: center; min-width: 140px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><th></th>{{range .Days}}<th colspan="{{.Span}}">{{.Name}}</th>{{end}}</tr>
+{{range .Rows}}<tr><td class="time">{{.Label}}</td>{{range .Cells}}{{if .Entry}}<td class="entry" rowspan="{{.Rows}}">{{range $i, $l := .Lines}}{{if $i}}<br>{{end}}{{$l}}{{end}}</td>{{else}}<td></td>{{end}}{{end}}</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type htmlDay struct {
+	Name string
+	Span int
+}
+
+type htmlCell struct {
+	Entry bool
+	Rows  int
+	Lines []string
+}
+
+type htmlRow struct {
+	Label string
+	Cells []*htmlCell
+}
+
+// WriteHTML writes a grid to w as a standalone HTML page.
+// Cells of meetings span rows of all their time slots.
+func (g *Grid) WriteHTML(w io.Writer) error {
+	l := g.Layout()
+	data := struct {
+		Title string
+		Days  []*htmlDay
+		Rows  []*htmlRow
+	}{Title: g.Title}
+	for d, n := range l.Days {
+		data.Days = append(data.Days, &htmlDay{Name: weekdays[d].String(), Span: n})
+	}
+	starts := make(map[[2]int]*Cell)
+	covered := make(map[[2]int]bool)
+	for _, c := range l.Cells {
+		starts[[2]int{c.Row, c.Column}] = c
+		for r := c.Row + 1; r < c.Row+c.Rows; r++ {
+			covered[[2]int{r, c.Column}] = true
+		}
+	}
+	for i := 0; i < l.Slots; i++ {
+		r := &htmlRow{Label: l.SlotLabel(i)}
+		for col := 0; col < l.Columns(); col++ {
+			if c := starts[[2]int{i, col}]; c != nil {
+				r.Cells = append(r.Cells, &htmlCell{Entry: true, Rows: c.Rows, Lines: c.Entry.Lines()})
+				continue
+			}
+			if !covered[[2]int{i, col}] {
+				r.Cells = append(r.Cells, &htmlCell{})
+			}
+		}
+		data.Rows = append(data.Rows, r)
+	}
+	return page.Execute(w, data)
+}
+
+// SaveHTML saves a grid as n.html file in p directory.
+func (g *Grid) SaveHTML(n, p string) error {
+	f, err := os.Create(filepath.Join(p, n+".html"))
+	if err != nil {
+		return err
+	}
+	if err := g.WriteHTML(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
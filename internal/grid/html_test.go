@@ -0,0 +1,32 @@
+package grid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGrid_WriteHTML(t *testing.T) {
+	g, _ := newTestGrid()
+	var b strings.Builder
+	if err := g.WriteHTML(&b); err != nil {
+		t.Fatalf("Grid.WriteHTML() error = %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		`<th colspan="2">Monday</th>`,
+		`<td class="entry" rowspan="6">Math<br>Class 1<br>T</td>`,
+		`<td class="entry" rowspan="2">Math<br>Laboratory 3<br>B-2</td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Grid.WriteHTML() does not contain %s", want)
+		}
+	}
+	// Every row has a time label and 6 columns, where cells spanning multiple rows are counted once
+	rows := strings.Split(got, "<tr>")[2:]
+	covered := []int{0, 1, 1, 1, 1, 2, 1, 2}
+	for i, r := range rows {
+		if n := strings.Count(r, "<td"); n != 7-covered[i] {
+			t.Errorf("Grid.WriteHTML() row %d has %d cells, want %d", i, n, 7-covered[i])
+		}
+	}
+}
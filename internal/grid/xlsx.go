@@ -0,0 +1,70 @@
+package grid
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+)
+
+const (
+	headerStyle = `{"font":{"bold":true},"alignment":{"horizontal":"center","vertical":"center"},"border":[{"type":"bottom","color":"000000","style":1}]}`
+	entryStyle  = `{"alignment":{"horizontal":"center","vertical":"center","wrap_text":true},"fill":{"type":"pattern","color":["#DDEBF7"],"pattern":1},"border":[{"type":"left","color":"5B9BD5","style":1},{"type":"top","color":"5B9BD5","style":1},{"type":"bottom","color":"5B9BD5","style":1},{"type":"right","color":"5B9BD5","style":1}]}`
+)
+
+// SaveXLSX saves a grid as a sheet of n.xlsx file in p directory.
+// Every weekday is a column and every time slot is a row, cells of meetings are merged across their time span.
+func (g *Grid) SaveXLSX(n, p string) error {
+	rp, err := filepath.Abs(p)
+	if err != nil {
+		return err
+	}
+	l := g.Layout()
+	s := sheetName(g.Title)
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", s)
+
+	hs, err := f.NewStyle(headerStyle)
+	if err != nil {
+		return err
+	}
+	es, err := f.NewStyle(entryStyle)
+	if err != nil {
+		return err
+	}
+
+	// Header with weekdays, a weekday with overlapping meetings spans multiple columns
+	col := 1
+	for d, n := range l.Days {
+		from, to := axis(col, 1), axis(col+n-1, 1)
+		f.SetCellStr(s, from, weekdays[d].String())
+		if n > 1 {
+			f.MergeCell(s, from, to)
+		}
+		f.SetCellStyle(s, from, to, hs)
+		col += n
+	}
+	f.SetColWidth(s, "A", "A", 8)
+	if c := l.Columns(); c > 0 {
+		f.SetColWidth(s, excelize.ToAlphaString(1), excelize.ToAlphaString(c), 24)
+	}
+	for i := 0; i < l.Slots; i++ {
+		f.SetCellStr(s, axis(0, i+2), l.SlotLabel(i))
+	}
+	for _, c := range l.Cells {
+		from, to := axis(c.Column+1, c.Row+2), axis(c.Column+1, c.Row+c.Rows+1)
+		f.SetCellStr(s, from, strings.Join(c.Entry.Lines(), "\n"))
+		if c.Rows > 1 {
+			f.MergeCell(s, from, to)
+		}
+		f.SetCellStyle(s, from, to, es)
+	}
+	f.SetPanes(s, `{"freeze":true,"split":false,"x_split":1,"y_split":1,"top_left_cell":"B2","active_pane":"bottomRight"}`)
+	return f.SaveAs(filepath.Join(rp, fmt.Sprintf("%s.xlsx", n)))
+}
+
+// axis returns a name of a cell with 0-based column and 1-based row, e.g. B2.
+func axis(col, row int) string {
+	return fmt.Sprintf("%s%d", excelize.ToAlphaString(col), row)
+}
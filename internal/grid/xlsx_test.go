@@ -0,0 +1,46 @@
+package grid
+
+import (
+	"os"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+)
+
+func TestGrid_SaveXLSX(t *testing.T) {
+	p := "./tmp"
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		os.Mkdir(p, os.ModePerm)
+	}
+	defer os.RemoveAll(p)
+	g, _ := newTestGrid()
+	if err := g.SaveXLSX("a", p); err != nil {
+		t.Fatalf("Grid.SaveXLSX() error = %v", err)
+	}
+	f, err := excelize.OpenFile(p + "/a.xlsx")
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	cells := map[string]string{
+		"B1": "Monday",
+		"D1": "Tuesday",
+		"A2": "08:00",
+		"B2": "Math\nClass 1\nT",
+		"C6": "Math\nClass 2\nT",
+		"E8": "Math\nLaboratory 3\nB-2",
+	}
+	for a, want := range cells {
+		if got := f.GetCellValue("a", a); got != want {
+			t.Errorf("Grid.SaveXLSX() cell %s = %q, want %q", a, got, want)
+		}
+	}
+	merged := make(map[string]bool)
+	for _, m := range f.GetMergeCells("a") {
+		merged[m.GetStartAxis()+":"+m.GetEndAxis()] = true
+	}
+	for _, want := range []string{"B1:C1", "B2:B7", "C6:C9", "E8:E9"} {
+		if !merged[want] {
+			t.Errorf("Grid.SaveXLSX() cells %s are not merged, merged: %v", want, merged)
+		}
+	}
+}
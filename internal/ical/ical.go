@@ -0,0 +1,204 @@
+// Package ical writes timetables as RFC 5545 iCalendar files.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+const (
+	dateTimeLayout = "20060102T150405"
+	stampLayout    = "20060102T150405Z"
+	// lineLength is the maximum length of a content line in octets, longer lines are folded.
+	lineLength = 75
+)
+
+// Calendar represents one iCalendar file.
+// Name - name of a calendar, e.g. a student name.
+// Stamp - time at which a calendar was created, it is written as DTSTAMP of every event.
+type Calendar struct {
+	Name   string
+	Stamp  time.Time
+	events []*event
+}
+
+// event represents one recurring meeting of a group.
+type event struct {
+	uid      string
+	summary  string
+	location string
+	teacher  string
+	start    time.Time
+	end      time.Time
+	until    time.Time
+	interval int
+}
+
+// New creates a new instance of Calendar.
+func New(n string) *Calendar {
+	return &Calendar{
+		Name:  n,
+		Stamp: time.Now().UTC(),
+	}
+}
+
+// ForStudent creates a calendar with final lecture sections and groups of a student.
+func ForStudent(st *university.Student) *Calendar {
+	c := New(st.Name)
+	var subs []string
+	for k := range st.FinalGroups {
+		subs = append(subs, k)
+	}
+	for k := range st.FinalLectures {
+		if _, ok := st.FinalGroups[k]; !ok {
+			subs = append(subs, k)
+		}
+	}
+	sort.Strings(subs)
+	for _, sub := range subs {
+		for _, g := range []*university.Group{st.FinalLectures[sub], st.FinalGroups[sub]} {
+			if g != nil {
+				c.AddGroup(sub, g)
+			}
+		}
+	}
+	return c
+}
+
+// ForGroup creates a calendar with meetings of one group of a subject.
+func ForGroup(sub string, g *university.Group) *Calendar {
+	c := New(fmt.Sprintf("%s %s", sub, g.Name))
+	c.AddGroup(sub, g)
+	return c
+}
+
+// AddGroup adds one recurring event for every meeting of a group.
+// Events are repeated every Frequency weeks until the last date returned by Group.Dates.
+// Meetings without a start date are skipped, because the first occurrence cannot be determined.
+func (c *Calendar) AddGroup(sub string, g *university.Group) {
+	for i, m := range g.Meetings() {
+		if m.StartDate.IsZero() {
+			continue
+		}
+		ds := m.Dates()
+		if len(ds) == 0 {
+			continue
+		}
+		f := m.Frequency
+		if f < 1 {
+			f = 1
+		}
+		c.events = append(c.events, &event{
+			uid:      uid(sub, g, i),
+			summary:  fmt.Sprintf("%s - %s %s", sub, g.Type, g.Name),
+			location: m.Place,
+			teacher:  m.Teacher,
+			start:    at(ds[0], m.StartTime),
+			end:      at(ds[0], m.EndTime),
+			until:    at(ds[len(ds)-1], m.StartTime),
+			interval: f,
+		})
+	}
+}
+
+// at returns a date with hour and minute of t.
+func at(d, t time.Time) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// uid returns a unique identifier of a meeting.
+// Every byte of names other than an ASCII letter or digit is percent-encoded, including the separator,
+// so different names, e.g. with national characters, never give the same identifier.
+func uid(sub string, g *university.Group, i int) string {
+	encode := func(s string) string {
+		var b strings.Builder
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+				b.WriteByte(c)
+				continue
+			}
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+		return b.String()
+	}
+	return fmt.Sprintf("%s-%s-%s-%d@scheduler", encode(sub), encode(string(g.Type)), encode(g.Name), i)
+}
+
+// Write writes a calendar to w.
+func (c *Calendar) Write(w io.Writer) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//pbartkowicz//scheduler//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escape(c.Name),
+	}
+	for _, e := range c.events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+e.uid,
+			"DTSTAMP:"+c.Stamp.UTC().Format(stampLayout),
+			"DTSTART:"+e.start.Format(dateTimeLayout),
+			"DTEND:"+e.end.Format(dateTimeLayout),
+			fmt.Sprintf("RRULE:FREQ=WEEKLY;INTERVAL=%d;UNTIL=%s", e.interval, e.until.Format(dateTimeLayout)),
+			"SUMMARY:"+escape(e.summary),
+		)
+		if e.location != "" {
+			lines = append(lines, "LOCATION:"+escape(e.location))
+		}
+		if e.teacher != "" {
+			lines = append(lines, "DESCRIPTION:"+escape("Teacher: "+e.teacher))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+	for _, l := range lines {
+		if _, err := io.WriteString(w, fold(l)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save writes a calendar to n.ics file in p directory.
+func (c *Calendar) Save(n, p string) error {
+	f, err := os.Create(filepath.Join(p, n+".ics"))
+	if err != nil {
+		return err
+	}
+	if err := c.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// escape escapes special characters of a text value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// fold splits a content line longer than lineLength octets, every next line starts with a space.
+// Lines are never split within a multi-byte character.
+func fold(l string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range l {
+		s := string(r)
+		if n+len(s) > lineLength {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteString(s)
+		n += len(s)
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+func TestCalendar_Write(t *testing.T) {
+	g := &university.Group{
+		Type:      university.Class,
+		Teacher:   "dr Smith, J.",
+		Weekday:   time.Wednesday,
+		StartTime: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC),
+		Place:     "B-4 H113",
+		StartDate: time.Date(2020, 2, 24, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+		Frequency: 2,
+		Name:      "1",
+		SubGroups: []*university.Group{
+			{
+				Type:      university.Class,
+				Weekday:   time.Friday,
+				StartTime: time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+				Name:      "1",
+			},
+		},
+	}
+	st := &university.Student{
+		Name:        "a",
+		FinalGroups: map[string]*university.Group{"Math": g},
+	}
+	c := ForStudent(st)
+	c.Stamp = time.Date(2020, 2, 1, 12, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	if err := c.Write(&b); err != nil {
+		t.Fatalf("Calendar.Write() error = %v", err)
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//pbartkowicz//scheduler//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:a",
+		"BEGIN:VEVENT",
+		"UID:Math-Class-1-0@scheduler",
+		"DTSTAMP:20200201T120000Z",
+		"DTSTART:20200226T100000",
+		"DTEND:20200226T113000",
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=20200325T100000",
+		"SUMMARY:Math - Class 1",
+		"LOCATION:B-4 H113",
+		`DESCRIPTION:Teacher: dr Smith\, J.`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := b.String(); !cmp.Equal(got, want) {
+		t.Errorf("Calendar.Write() diff = %s", cmp.Diff(got, want))
+	}
+}
+
+func Test_fold(t *testing.T) {
+	tests := []struct {
+		name string
+		l    string
+		want string
+	}{
+		{
+			name: "Does not fold short lines",
+			l:    "SUMMARY:Math",
+			want: "SUMMARY:Math",
+		},
+		{
+			name: "Folds long lines",
+			l:    strings.Repeat("a", 80),
+			want: strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 5),
+		},
+		{
+			name: "Does not split multi-byte characters",
+			l:    strings.Repeat("a", 74) + "ż",
+			want: strings.Repeat("a", 74) + "\r\n ż",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fold(tt.l); got != tt.want {
+				t.Errorf("fold() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_uid(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  string
+		g    *university.Group
+		want string
+	}{
+		{
+			name: "Keeps letters and digits",
+			sub:  "Math",
+			g:    &university.Group{Name: "1a", Type: university.Class},
+			want: "Math-Class-1a-0@scheduler",
+		},
+		{
+			name: "Encodes national characters and spaces",
+			sub:  "Architektura przedsięwzięcia",
+			g:    &university.Group{Name: "1", Type: university.Lecture},
+			want: "Architektura%20przedsi%C4%99wzi%C4%99cia-Lecture-1-0@scheduler",
+		},
+		{
+			name: "Encodes separators, so names which differ only in them do not collide",
+			sub:  "Math-Class",
+			g:    &university.Group{Name: "1", Type: ""},
+			want: "Math%2DClass--1-0@scheduler",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uid(tt.sub, tt.g, 0); got != tt.want {
+				t.Errorf("uid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
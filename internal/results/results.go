@@ -0,0 +1,100 @@
+// Package results converts outcomes of enrollment to rows of result files.
+package results
+
+import (
+	"fmt"
+
+	"github.com/pbartkowicz/scheduler/internal/university"
+	"github.com/pbartkowicz/scheduler/internal/xlsx"
+)
+
+// Workbook writes Students, Groups, Waitlists, Teachers, Teacher hours, Occupancy, Rooms, Unassigned and Summary sheets
+// to one workbook. The Changes sheet is added when a baseline is used, after incremental enrollment
+// or a run with a baseline, see university.Schedule.Reenroll and university.Schedule.Baseline.
+// Rows of every sheet are created just before the sheet is written, so only one sheet is kept in memory at once.
+// The workbook is not closed.
+func Workbook(w *xlsx.Workbook, schedule *university.Schedule, students []*university.Student, res *university.EnrollResult) error {
+	sheets := []struct {
+		name   string
+		header []string
+		rows   func() [][]string
+	}{
+		{"Students", []string{"student", "subject", "group", "type", "weekday", "start time", "end time", "place", "rank"}, func() (rs [][]string) {
+			for _, st := range students {
+				rs = append(rs, st.Timetable()...)
+			}
+			return
+		}},
+		{"Groups", []string{"subject", "group", "type", "student", "priority"}, func() (rs [][]string) {
+			for _, sub := range schedule.Subjects {
+				for _, gs := range [][]*university.Group{sub.Lectures, sub.Groups} {
+					for _, g := range gs {
+						rs = append(rs, g.Roster(sub.Name)...)
+					}
+				}
+			}
+			return
+		}},
+		{"Waitlists", []string{"subject", "group", "type", "position", "student"}, func() [][]string {
+			return Waitlists(schedule)
+		}},
+		{"Teachers", []string{"teacher", "subject", "group", "type", "weekday", "start time", "end time", "place", "frequency", "students"}, func() [][]string {
+			ts, _ := Teachers(schedule)
+			return ts
+		}},
+		{"Teacher hours", []string{"teacher", "weekly hours"}, func() [][]string {
+			_, hs := Teachers(schedule)
+			return hs
+		}},
+		{"Occupancy", []string{"room", "subject", "group", "type", "weekday", "start time", "end time", "teacher", "frequency", "students"}, func() [][]string {
+			ms, _ := Occupancy(schedule)
+			return ms
+		}},
+		{"Rooms", []string{"room", "capacity", "features", "weekly hours"}, func() [][]string {
+			_, rs := Occupancy(schedule)
+			return rs
+		}},
+		{"Unassigned", []string{"student", "subject", "type", "reason"}, res.Save},
+		{"Changes", []string{"student", "subject", "type", "from", "to", "reason"}, res.SaveChanges},
+		{"Summary", []string{"statistic", "value"}, res.Fairness.Save},
+	}
+	for _, sh := range sheets {
+		if sh.name == "Changes" && res.Changes == nil {
+			continue
+		}
+		if err := w.AddSheet(sh.name, append([][]string{sh.header}, sh.rows()...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Waitlists creates a slice with waitlisted students of all groups and lecture sections in order.
+func Waitlists(schedule *university.Schedule) (res [][]string) {
+	for _, sub := range schedule.Subjects {
+		for _, gs := range [][]*university.Group{sub.Lectures, sub.Groups} {
+			for _, g := range gs {
+				res = append(res, g.SaveWaitlist(sub.Name)...)
+			}
+		}
+	}
+	return
+}
+
+// Teachers creates slices with meetings of all teachers and their weekly hours.
+func Teachers(schedule *university.Schedule) (ts [][]string, hs [][]string) {
+	for _, t := range schedule.Teachers() {
+		ts = append(ts, t.Save()...)
+		hs = append(hs, []string{t.Name, fmt.Sprintf("%.2f", t.Hours())})
+	}
+	return
+}
+
+// Occupancy creates slices with meetings held in every room and a summary of every room.
+func Occupancy(schedule *university.Schedule) (ms [][]string, rs [][]string) {
+	for _, r := range schedule.Occupancy() {
+		ms = append(ms, r.Save()...)
+		rs = append(rs, r.Summary())
+	}
+	return
+}
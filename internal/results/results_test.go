@@ -0,0 +1,73 @@
+package results
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+func TestTeachers(t *testing.T) {
+	s, err := university.NewSchedule([][]string{
+		{"Math", "Lecture", "T", "Monday", "8:00", "9:30", "A-1", "03-02-20", "1", "Lecture", "10"},
+		{"Math", "Class", "U", "Tuesday", "10:00", "11:30", "B-2", "03-03-20", "2", "1", "5"},
+	})
+	if err != nil {
+		t.Fatalf("NewSchedule() error = %v", err)
+	}
+	ts, hs := Teachers(s)
+	wantTs := [][]string{
+		{"T", "Math", "Lecture", "Lecture", "Monday", "08:00", "09:30", "A-1", "1", "0"},
+		{"U", "Math", "1", "Class", "Tuesday", "10:00", "11:30", "B-2", "2", "0"},
+	}
+	wantHs := [][]string{{"T", "1.50"}, {"U", "0.75"}}
+	if diff := cmp.Diff(wantTs, ts); diff != "" {
+		t.Errorf("Teachers() meetings mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantHs, hs); diff != "" {
+		t.Errorf("Teachers() hours mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOccupancy(t *testing.T) {
+	s, err := university.NewSchedule([][]string{
+		{"Math", "Lecture", "T", "Monday", "8:00", "9:30", "A-1", "03-02-20", "1", "Lecture", "10"},
+		{"Math", "Class", "U", "Tuesday", "10:00", "11:30", "B-2", "03-03-20", "2", "1", "5"},
+	})
+	if err != nil {
+		t.Fatalf("NewSchedule() error = %v", err)
+	}
+	s.Rooms = []*university.Room{{Name: "A-1", Capacity: 30, Features: []string{"projector"}}}
+	ms, rs := Occupancy(s)
+	wantMs := [][]string{
+		{"A-1", "Math", "Lecture", "Lecture", "Monday", "08:00", "09:30", "T", "1", "0"},
+		{"B-2", "Math", "1", "Class", "Tuesday", "10:00", "11:30", "U", "2", "0"},
+	}
+	wantRs := [][]string{{"A-1", "30", "projector", "1.50"}, {"B-2", "", "", "0.75"}}
+	if diff := cmp.Diff(wantMs, ms); diff != "" {
+		t.Errorf("Occupancy() meetings mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantRs, rs); diff != "" {
+		t.Errorf("Occupancy() rooms mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWaitlists(t *testing.T) {
+	s := &university.Schedule{
+		Subjects: []*university.Subject{
+			{
+				Name:     "Math",
+				Lectures: []*university.Group{{Name: "Lecture", Type: university.Lecture, Waitlist: []*university.Student{{Name: "a"}}}},
+				Groups:   []*university.Group{{Name: "1", Type: university.Class, Waitlist: []*university.Student{{Name: "b"}, {Name: "a"}}}},
+			},
+		},
+	}
+	want := [][]string{
+		{"Math", "Lecture", "Lecture", "1", "a"},
+		{"Math", "1", "Class", "1", "b"},
+		{"Math", "1", "Class", "2", "a"},
+	}
+	if diff := cmp.Diff(want, Waitlists(s)); diff != "" {
+		t.Errorf("Waitlists() mismatch (-want +got):\n%s", diff)
+	}
+}
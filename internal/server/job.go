@@ -0,0 +1,191 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pbartkowicz/scheduler/internal/university"
+	"github.com/pbartkowicz/scheduler/internal/validation"
+)
+
+// Status is a state of a job.
+type Status string
+
+const (
+	// StatusCreated - files of a job can be uploaded.
+	StatusCreated Status = "created"
+	// StatusRunning - students are being enrolled.
+	StatusRunning Status = "running"
+	// StatusDone - students were enrolled and results can be downloaded.
+	StatusDone Status = "done"
+	// StatusFailed - files of a job are invalid or enrollment failed.
+	StatusFailed Status = "failed"
+)
+
+// Options contains names of algorithms used to enroll students, see university.NewSolver,
+// university.NewSatisfactionModel and university.NewFairnessPolicy.
+// End - end date of the semester in the same format as a start date of a group, optional.
+type Options struct {
+	Solver       string `json:"solver"`
+	Satisfaction string `json:"satisfaction"`
+	Fairness     string `json:"fairness"`
+	End          string `json:"end"`
+}
+
+// Job represents one enrollment with its input files and results.
+// All fields are guarded by mu, done is closed when a job is finished.
+// touched is the time of the last request to a job, it is guarded by the mutex of a Server.
+type Job struct {
+	mu          sync.Mutex
+	done        chan struct{}
+	id          string
+	status      Status
+	err         string
+	diagnostics []*validation.Diagnostic
+
+	groups   *validation.Table
+	priority *validation.Table
+	rooms    *validation.Table
+	students map[string]*validation.Table
+
+	schedule *university.Schedule
+	enrolled []*university.Student
+	result   *university.EnrollResult
+
+	touched time.Time
+}
+
+func newJob(id string) *Job {
+	return &Job{
+		id:       id,
+		done:     make(chan struct{}),
+		status:   StatusCreated,
+		students: make(map[string]*validation.Table),
+	}
+}
+
+// start marks a job as running, it returns false when a job was already started.
+func (j *Job) start() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != StatusCreated {
+		return false
+	}
+	j.status = StatusRunning
+	return true
+}
+
+// running checks if students of a job are being enrolled.
+func (j *Job) running() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status == StatusRunning
+}
+
+// run validates files of a job and enrolls students.
+func (j *Job) run(o *Options) {
+	defer close(j.done)
+	j.mu.Lock()
+	groups, priority, rooms := j.groups, j.priority, j.rooms
+	var names []string
+	for n := range j.students {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var sts []*validation.Table
+	for _, n := range names {
+		sts = append(sts, j.students[n])
+	}
+	j.mu.Unlock()
+
+	sch, students, res, rep, err := enroll(groups, sts, priority, rooms, o)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if rep != nil {
+		j.diagnostics = rep.Diagnostics
+	}
+	if err != nil {
+		j.status = StatusFailed
+		j.err = err.Error()
+		return
+	}
+	j.status = StatusDone
+	j.schedule, j.enrolled, j.result = sch, students, res
+}
+
+// enroll creates a schedule and students from validated tables and enrolls students using passed options.
+// It returns a validation report when tables contain any problem.
+func enroll(groups *validation.Table, sts []*validation.Table, priority, rooms *validation.Table, o *Options) (*university.Schedule, []*university.Student, *university.EnrollResult, *validation.Report, error) {
+	if groups == nil {
+		return nil, nil, nil, nil, ErrNoGroups
+	}
+	rep := validation.Validate(groups, sts, priority, rooms)
+	if !rep.OK() {
+		return nil, nil, nil, rep, fmt.Errorf("found %d problems in files", len(rep.Diagnostics))
+	}
+	sch, err := university.NewSchedule(groups.Rows)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if rooms != nil {
+		for _, r := range rooms.Rows {
+			room, err := university.NewRoom(r)
+			if err != nil {
+				return nil, nil, nil, nil, err
+			}
+			sch.Rooms = append(sch.Rooms, room)
+		}
+		if err := sch.ValidateRooms(); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	if o.End != "" {
+		end, err := university.ParseDate(o.End)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		sch.SetEndDate(end)
+	}
+	if sch.Solver, err = university.NewSolver(orDefault(o.Solver, "greedy")); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if sch.Satisfaction, err = university.NewSatisfactionModel(orDefault(o.Satisfaction, "linear")); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if sch.Fairness, err = university.NewFairnessPolicy(orDefault(o.Fairness, "none")); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	var students []*university.Student
+	for _, t := range sts {
+		st, err := university.NewNamedStudent(t.Rows, t.StudentName())
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		students = append(students, st)
+	}
+	if priority != nil {
+		for _, p := range priority.Rows {
+			for _, st := range students {
+				if st.Name == p[0] {
+					st.Priority = true
+				}
+			}
+		}
+	}
+	res, err := sch.Enroll(students)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return sch, students, res, nil, nil
+}
+
+func orDefault(v, d string) string {
+	if v == "" {
+		return d
+	}
+	return v
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/university"
+	"github.com/pbartkowicz/scheduler/internal/validation"
+)
+
+// ContentTypeForm is the content type of preferences submitted by an HTML form.
+const ContentTypeForm = "application/x-www-form-urlencoded"
+
+var (
+	// ErrNoStudentName is returned when preferences are submitted without a student name.
+	ErrNoStudentName = errors.New("student name is required")
+	// ErrWrongPreferences is returned when submitted preferences contain problems.
+	ErrWrongPreferences = errors.New("preferences contain problems")
+)
+
+// Submission represents preferences submitted by a student.
+// The name of a student is passed explicitly, it does not depend on a file name.
+type Submission struct {
+	Name        string                 `json:"name"`
+	Preferences []*document.Preference `json:"preferences"`
+}
+
+// rows converts a submission to rows in the format expected by university.NewStudent.
+func (s *Submission) rows() [][]string {
+	res := make([][]string, len(s.Preferences))
+	for i, p := range s.Preferences {
+		res[i] = []string{p.Subject, p.Group, strconv.Itoa(p.Priority)}
+	}
+	return res
+}
+
+// submissionResult is returned after preferences are submitted.
+type submissionResult struct {
+	Error       string                   `json:"error,omitempty"`
+	Saved       bool                     `json:"saved"`
+	Diagnostics []*validation.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// catalog creates a schedule from uploaded groups, it is used to list subjects and validate preferences.
+func (j *Job) catalog() (*university.Schedule, error) {
+	j.mu.Lock()
+	groups := j.groups
+	j.mu.Unlock()
+	if groups == nil {
+		return nil, ErrNoGroups
+	}
+	return university.NewSchedule(groups.Rows)
+}
+
+// subjects writes subjects of a job together with their lecture sections and groups.
+func (s *Server) subjects(w http.ResponseWriter, j *Job) {
+	sch, err := j.catalog()
+	if err != nil {
+		writeError(w, catalogStatus(err), err)
+		return
+	}
+	subs := document.New(sch, nil).Subjects
+	if subs == nil {
+		subs = []*document.Subject{}
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// preferences validates preferences of a student and saves them as a student table of a job.
+// Preferences are passed as JSON Submission or as an HTML form with name and repeated subject, group and priority fields,
+// groups with an empty priority are skipped.
+// With the validate query parameter set to true preferences are only validated.
+// The response is an HTML page when a client accepts text/html, e.g. when the form is submitted without scripts.
+func (s *Server) preferences(w http.ResponseWriter, r *http.Request, j *Job) {
+	html := strings.Contains(r.Header.Get("Accept"), "text/html")
+	sch, err := j.catalog()
+	if err != nil {
+		writeError(w, catalogStatus(err), err)
+		return
+	}
+	sub, err := readSubmission(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	res, code := &submissionResult{}, http.StatusOK
+	if sub.Name == "" {
+		res.Error, code = ErrNoStudentName.Error(), http.StatusUnprocessableEntity
+	} else {
+		t := &validation.Table{File: "students/" + sub.Name, Sheet: "preferences", Name: sub.Name, Rows: sub.rows()}
+		if rep := validation.ValidateStudent(sch, t); !rep.OK() {
+			res.Error, res.Diagnostics, code = ErrWrongPreferences.Error(), rep.Diagnostics, http.StatusUnprocessableEntity
+		} else if r.URL.Query().Get("validate") != "true" {
+			if err := j.save(t); err != nil {
+				writeError(w, http.StatusConflict, err)
+				return
+			}
+			res.Saved, code = true, http.StatusCreated
+		}
+	}
+	if html {
+		writeForm(w, code, j, sch, sub, res)
+		return
+	}
+	writeJSON(w, code, res)
+}
+
+// save saves preferences of a student, previous preferences of the student are replaced.
+func (j *Job) save(t *validation.Table) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != StatusCreated {
+		return ErrJobStarted
+	}
+	j.students[t.Name] = t
+	return nil
+}
+
+func readSubmission(w http.ResponseWriter, r *http.Request) (*Submission, error) {
+	ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, ErrWrongContentType
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+	sub := &Submission{}
+	switch ct {
+	case ContentTypeJSON:
+		if err := json.NewDecoder(r.Body).Decode(sub); err != nil {
+			return nil, err
+		}
+	case ContentTypeForm:
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		sub.Name = strings.TrimSpace(r.PostForm.Get("name"))
+		subs, grs, ps := r.PostForm["subject"], r.PostForm["group"], r.PostForm["priority"]
+		for i := 0; i < len(subs) && i < len(grs) && i < len(ps); i++ {
+			v := strings.TrimSpace(ps[i])
+			if v == "" {
+				continue
+			}
+			p, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, err
+			}
+			sub.Preferences = append(sub.Preferences, &document.Preference{Subject: subs[i], Group: grs[i], Priority: p})
+		}
+	default:
+		return nil, ErrWrongContentType
+	}
+	return sub, nil
+}
+
+// catalogStatus returns a status code of a response when subjects of a job cannot be read.
+func catalogStatus(err error) int {
+	if errors.Is(err, ErrNoGroups) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// form writes an HTML form in which a student ranks groups of a job.
+func (s *Server) form(w http.ResponseWriter, j *Job) {
+	sch, err := j.catalog()
+	if err != nil {
+		writeError(w, catalogStatus(err), err)
+		return
+	}
+	writeForm(w, http.StatusOK, j, sch, &Submission{}, nil)
+}
+
+var formPage = template.Must(template.New("form").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Preferences</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 16px; }
+th, td { border: 1px solid #d0d0d0; padding: 2px 6px; font-size: 13px; text-align: left; }
+th { background: #f2f2f2; }
+input[type=number] { width: 4em; }
+#problems { color: #c00000; }
+#saved { color: #008000; }
+</style>
+</head>
+<body>
+<h1>Preferences</h1>
+<p>Rank groups of every subject starting from 1 (the most preferred). Ranks have to be consecutive and they can be repeated. Leave a rank empty to skip a group.</p>
+<form id="preferences" method="post" action="/jobs/{{.ID}}/preferences">
+<p><label>Name <input name="name" value="{{.Name}}" required></label></p>
+{{range .Subjects}}<h2>{{.Name}}</h2>
+<table>
+<tr><th>Group</th><th>Type</th><th>Meetings</th><th>Rank</th></tr>
+{{$s := .Name}}{{range .Groups}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{range $i, $m := .Meetings}}{{if $i}}<br>{{end}}{{$m.Weekday}} {{$m.StartTime}}-{{$m.EndTime}} {{$m.Place}} {{$m.Teacher}}{{end}}</td>
+<td><input type="hidden" name="subject" value="{{$s}}"><input type="hidden" name="group" value="{{.Name}}"><input type="number" min="1" name="priority" value="{{.Priority}}"></td></tr>
+{{end}}</table>
+{{end}}<ul id="problems">{{range .Problems}}<li>{{.}}</li>{{end}}</ul>
+<p id="saved">{{if .Saved}}Preferences were saved.{{end}}</p>
+<button type="submit">Submit</button>
+</form>
+<script>
+var form = document.getElementById("preferences");
+form.addEventListener("change", function () {
+  fetch(form.action + "?validate=true", {method: "POST", body: new URLSearchParams(new FormData(form))})
+    .then(function (r) { return r.json(); })
+    .then(function (res) {
+      var ul = document.getElementById("problems");
+      ul.innerHTML = "";
+      document.getElementById("saved").textContent = "";
+      (res.diagnostics || []).forEach(function (d) {
+        var li = document.createElement("li");
+        li.textContent = d.message;
+        ul.appendChild(li);
+      });
+      if (res.error && !res.diagnostics && form.elements.name.value) {
+        var li = document.createElement("li");
+        li.textContent = res.error;
+        ul.appendChild(li);
+      }
+    });
+});
+</script>
+</body>
+</html>
+`))
+
+type formGroup struct {
+	*document.Group
+	Priority string
+}
+
+type formSubject struct {
+	Name   string
+	Groups []*formGroup
+}
+
+// writeForm writes the form with ranks of a submission and problems found in it.
+func writeForm(w http.ResponseWriter, code int, j *Job, sch *university.Schedule, sub *Submission, res *submissionResult) {
+	ranks := make(map[university.SubjectGroup]int)
+	for _, p := range sub.Preferences {
+		ranks[university.SubjectGroup{Subject: p.Subject, Group: p.Group}] = p.Priority
+	}
+	data := struct {
+		ID       string
+		Name     string
+		Subjects []*formSubject
+		Problems []string
+		Saved    bool
+	}{ID: j.id, Name: sub.Name}
+	for _, ds := range document.New(sch, nil).Subjects {
+		fs := &formSubject{Name: ds.Name}
+		for _, dgs := range [][]*document.Group{ds.Lectures, ds.Groups} {
+			for _, dg := range dgs {
+				fg := &formGroup{Group: dg}
+				if r, ok := ranks[university.SubjectGroup{Subject: ds.Name, Group: dg.Name}]; ok {
+					fg.Priority = strconv.Itoa(r)
+				}
+				fs.Groups = append(fs.Groups, fg)
+			}
+		}
+		data.Subjects = append(data.Subjects, fs)
+	}
+	if res != nil {
+		data.Saved = res.Saved
+		for _, d := range res.Diagnostics {
+			data.Problems = append(data.Problems, d.Message)
+		}
+		if res.Error != "" && len(res.Diagnostics) == 0 {
+			data.Problems = append(data.Problems, res.Error)
+		}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	formPage.Execute(w, data)
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/validation"
+)
+
+func TestServer_subjects(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	p := "/jobs/" + j.id
+	if w := do(t, s, http.MethodGet, p+"/subjects", "", ""); w.Code != http.StatusConflict {
+		t.Errorf("GET subjects without groups code = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := do(t, s, http.MethodGet, p+"/form", "", ""); w.Code != http.StatusConflict {
+		t.Errorf("GET form without groups code = %d, want %d", w.Code, http.StatusConflict)
+	}
+	do(t, s, http.MethodPut, p+"/groups", ContentTypeJSON, testGroups)
+
+	w := do(t, s, http.MethodGet, p+"/subjects", "", "")
+	var subs []*document.Subject
+	if err := json.NewDecoder(w.Body).Decode(&subs); err != nil {
+		t.Fatalf("GET subjects error = %v", err)
+	}
+	var got []string
+	for _, sub := range subs {
+		for _, g := range append(sub.Lectures, sub.Groups...) {
+			got = append(got, sub.Name+" "+g.Name)
+		}
+	}
+	if diff := cmp.Diff([]string{"Math Lecture", "Math 1", "Math 2"}, got); diff != "" {
+		t.Errorf("GET subjects mismatch (-want +got):\n%s", diff)
+	}
+
+	w = do(t, s, http.MethodGet, p+"/form", "", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `<input type="hidden" name="group" value="2">`) {
+		t.Errorf("GET form code = %d, body %s", w.Code, w.Body.String())
+	}
+}
+
+func TestServer_preferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		ct       string
+		body     string
+		code     int
+		want     *submissionResult
+		students map[string][][]string
+	}{
+		{
+			name: "Saves valid preferences",
+			ct:   ContentTypeJSON,
+			body: `{"name": "j.doe", "preferences": [{"subject": "Math", "group": "1", "priority": 2}, {"subject": "Math", "group": "2", "priority": 1}]}`,
+			code: http.StatusCreated,
+			want: &submissionResult{Saved: true},
+			students: map[string][][]string{
+				"j.doe": {{"Math", "1", "2"}, {"Math", "2", "1"}},
+			},
+		},
+		{
+			name:  "Only validates preferences",
+			query: "?validate=true",
+			ct:    ContentTypeJSON,
+			body:  `{"name": "aaa", "preferences": [{"subject": "Math", "group": "1", "priority": 1}]}`,
+			code:  http.StatusOK,
+			want:  &submissionResult{},
+		},
+		{
+			name: "Reads preferences from a form",
+			ct:   ContentTypeForm,
+			body: url.Values{
+				"name":     {"aaa"},
+				"subject":  {"Math", "Math", "Math"},
+				"group":    {"Lecture", "1", "2"},
+				"priority": {"", "1", "1"},
+			}.Encode(),
+			code: http.StatusCreated,
+			want: &submissionResult{Saved: true},
+			students: map[string][][]string{
+				"aaa": {{"Math", "1", "1"}, {"Math", "2", "1"}},
+			},
+		},
+		{
+			name: "Fails without a name",
+			ct:   ContentTypeJSON,
+			body: `{"preferences": [{"subject": "Math", "group": "1", "priority": 1}]}`,
+			code: http.StatusUnprocessableEntity,
+			want: &submissionResult{Error: ErrNoStudentName.Error()},
+		},
+		{
+			name: "Fails on priorities which are not consecutive",
+			ct:   ContentTypeJSON,
+			body: `{"name": "aaa", "preferences": [{"subject": "Math", "group": "1", "priority": 1}, {"subject": "Math", "group": "2", "priority": 3}]}`,
+			code: http.StatusUnprocessableEntity,
+			want: &submissionResult{
+				Error: ErrWrongPreferences.Error(),
+				Diagnostics: []*validation.Diagnostic{
+					{File: "students/aaa", Sheet: "preferences", Column: 3, Code: validation.CodeWrongPriority, Message: "incorrect priority for subject: priorities have to be consecutive with repetition"},
+				},
+			},
+		},
+		{
+			name: "Fails on unknown groups",
+			ct:   ContentTypeJSON,
+			body: `{"name": "aaa", "preferences": [{"subject": "Math", "group": "1", "priority": 1}, {"subject": "Math", "group": "3", "priority": 2}]}`,
+			code: http.StatusUnprocessableEntity,
+			want: &submissionResult{
+				Error: ErrWrongPreferences.Error(),
+				Diagnostics: []*validation.Diagnostic{
+					{File: "students/aaa", Sheet: "preferences", Row: 2, Column: 2, Code: validation.CodeUnknownGroup, Message: "group 3 of Math does not exist"},
+				},
+			},
+		},
+		{
+			name: "Fails on incorrect content type",
+			ct:   "text/plain",
+			body: "aaa",
+			code: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			j := newTestJob(t, s)
+			do(t, s, http.MethodPut, "/jobs/"+j.id+"/groups", ContentTypeJSON, testGroups)
+			w := do(t, s, http.MethodPost, "/jobs/"+j.id+"/preferences"+tt.query, tt.ct, tt.body)
+			if w.Code != tt.code {
+				t.Fatalf("POST preferences code = %d, want %d, body %s", w.Code, tt.code, w.Body.String())
+			}
+			if tt.want != nil {
+				got := &submissionResult{}
+				if err := json.NewDecoder(w.Body).Decode(got); err != nil {
+					t.Fatalf("POST preferences error = %v", err)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("POST preferences mismatch (-want +got):\n%s", diff)
+				}
+			}
+			students := make(map[string][][]string)
+			for n, st := range j.students {
+				students[n] = st.Rows
+			}
+			if tt.students == nil {
+				tt.students = map[string][][]string{}
+			}
+			if diff := cmp.Diff(tt.students, students); diff != "" {
+				t.Errorf("POST preferences students mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_preferencesForm(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	do(t, s, http.MethodPut, "/jobs/"+j.id+"/groups", ContentTypeJSON, testGroups)
+	body := url.Values{"name": {"aaa"}, "subject": {"Math"}, "group": {"3"}, "priority": {"1"}}.Encode()
+	r := httptest.NewRequest(http.MethodPost, "/jobs/"+j.id+"/preferences", strings.NewReader(body))
+	r.Header.Set("Content-Type", ContentTypeForm)
+	r.Header.Set("Accept", "text/html,application/xhtml+xml")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("POST form code = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	if got := w.Body.String(); !strings.Contains(got, "<li>group 3 of Math does not exist</li>") || !strings.Contains(got, `value="aaa"`) {
+		t.Errorf("POST form body = %s", got)
+	}
+}
+
+func TestServer_preferencesEnroll(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	p := "/jobs/" + j.id
+	do(t, s, http.MethodPut, p+"/groups", ContentTypeJSON, testGroups)
+	do(t, s, http.MethodPost, p+"/preferences", ContentTypeJSON, `{"name": "j.doe", "preferences": [{"subject": "Math", "group": "2", "priority": 1}, {"subject": "Math", "group": "1", "priority": 2}]}`)
+	do(t, s, http.MethodPost, p+"/run", "", "")
+	<-j.done
+	if j.status != StatusDone || len(j.enrolled) != 1 {
+		t.Fatalf("run() status = %s, error %s", j.status, j.err)
+	}
+	st := j.enrolled[0]
+	if st.Name != "j.doe" || st.FinalGroups["Math"] == nil || st.FinalGroups["Math"].Name != "2" {
+		t.Errorf("run() student = %s, groups %v", st.Name, st.FinalGroups)
+	}
+	if w := do(t, s, http.MethodPost, p+"/preferences", ContentTypeJSON, `{"name": "aaa", "preferences": [{"subject": "Math", "group": "1", "priority": 1}]}`); w.Code != http.StatusConflict {
+		t.Errorf("POST preferences after run code = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
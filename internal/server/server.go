@@ -0,0 +1,422 @@
+// Package server exposes enrollment as a REST API.
+// Jobs are kept in memory: files of a job are uploaded, the job is run in the background
+// and its results are downloaded when it is done. A job is removed when it is deleted
+// or when it was not requested for longer than Server.TTL.
+//
+// Endpoints:
+//
+//	POST   /jobs                        - create a job
+//	GET    /jobs/{id}                   - status of a job
+//	DELETE /jobs/{id}                   - remove a job which is not running
+//	PUT  /jobs/{id}/groups              - upload groups
+//	PUT  /jobs/{id}/students/{name}     - upload preferences of a student
+//	PUT  /jobs/{id}/priority            - upload priority students
+//	PUT  /jobs/{id}/rooms               - upload rooms
+//	GET  /jobs/{id}/subjects            - subjects and groups of uploaded groups
+//	GET  /jobs/{id}/form                - HTML form in which a student ranks groups
+//	POST /jobs/{id}/preferences         - validate and save preferences of a student
+//	POST /jobs/{id}/run                 - enroll students, the body may contain Options
+//	GET  /jobs/{id}/result?format=...   - download results as json, yaml, xlsx or ics
+//	POST /jobs/{id}/withdrawals         - withdraw a student from a subject and promote waitlisted students
+//	POST /jobs/{id}/trades              - exchange groups of students in pairs and cycles
+//	GET  /jobs/{id}/history             - changes of groups made after enrollment
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pbartkowicz/scheduler/internal/csv"
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/ical"
+	"github.com/pbartkowicz/scheduler/internal/results"
+	"github.com/pbartkowicz/scheduler/internal/validation"
+	"github.com/pbartkowicz/scheduler/internal/xlsx"
+)
+
+// Content types of uploaded and downloaded files.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeYAML     = "application/x-yaml"
+	ContentTypeCSV      = "text/csv"
+	ContentTypeXLSX     = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ContentTypeCalendar = "text/calendar"
+)
+
+// maxUpload is the maximum size of an uploaded file in bytes.
+const maxUpload = 10 << 20
+
+var (
+	// ErrJobNotFound is returned when a job does not exist.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobStarted is returned when files are uploaded or a job is run after it was started.
+	ErrJobStarted = errors.New("job was already started")
+	// ErrJobRunning is returned when a running job is deleted.
+	ErrJobRunning = errors.New("job is running")
+	// ErrJobNotDone is returned when results of a job are downloaded before it is done.
+	ErrJobNotDone = errors.New("job is not done")
+	// ErrNoGroups is returned when a job is run without groups.
+	ErrNoGroups = errors.New("groups were not uploaded")
+	// ErrWrongContentType is returned when an uploaded file has a content type which is not supported.
+	ErrWrongContentType = errors.New("incorrect content type, available types: " + ContentTypeJSON + ", " + ContentTypeCSV + ", " + ContentTypeXLSX)
+	// ErrWrongResultFormat is returned when results are downloaded in a format which is not supported.
+	ErrWrongResultFormat = errors.New("incorrect format, available formats: json, yaml, xlsx, ics")
+	// ErrNoTimetable is returned when a calendar is downloaded without a student or a group.
+	ErrNoTimetable = errors.New("calendar requires a student or a subject and a group")
+)
+
+// Server handles requests of the REST API.
+// It implements http.Handler.
+// TTL - time after the last request to a job after which the job is removed, running jobs are kept.
+// Jobs are kept until they are deleted when it is 0.
+type Server struct {
+	TTL time.Duration
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// New creates a new instance of Server.
+func New() *Server {
+	return &Server{jobs: make(map[string]*Job)}
+}
+
+// ServeHTTP routes a request to a handler based on its method and path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if p[0] != "jobs" {
+		writeError(w, http.StatusNotFound, ErrJobNotFound)
+		return
+	}
+	if len(p) == 1 {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.create(w)
+		return
+	}
+	j := s.job(p[1])
+	if j == nil {
+		writeError(w, http.StatusNotFound, ErrJobNotFound)
+		return
+	}
+	switch {
+	case len(p) == 2:
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, j.view())
+		case http.MethodDelete:
+			s.delete(w, j)
+		default:
+			methodNotAllowed(w, http.MethodGet+", "+http.MethodDelete)
+		}
+	case len(p) == 3 && (p[2] == "groups" || p[2] == "priority" || p[2] == "rooms"),
+		len(p) == 4 && p[2] == "students" && p[3] != "":
+		if r.Method != http.MethodPut {
+			methodNotAllowed(w, http.MethodPut)
+			return
+		}
+		s.upload(w, r, j, p[2:])
+	case len(p) == 3 && (p[2] == "subjects" || p[2] == "form"):
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		if p[2] == "subjects" {
+			s.subjects(w, j)
+		} else {
+			s.form(w, j)
+		}
+	case len(p) == 3 && p[2] == "preferences":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.preferences(w, r, j)
+	case len(p) == 3 && p[2] == "run":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.run(w, r, j)
+	case len(p) == 3 && p[2] == "result":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.result(w, r, j)
+	case len(p) == 3 && p[2] == "withdrawals":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.withdraw(w, r, j)
+	case len(p) == 3 && p[2] == "trades":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.trade(w, r, j)
+	case len(p) == 3 && p[2] == "history":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.history(w, j)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s", r.URL.Path))
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	j := newJob(hex.EncodeToString(b))
+	s.mu.Lock()
+	j.touched = time.Now()
+	s.evict(j.touched)
+	s.jobs[j.id] = j
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, j.view())
+}
+
+// job returns a job with a passed ID and records the time of a request to it, it returns nil when it does not exist.
+func (s *Server) job(id string) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.evict(now)
+	j := s.jobs[id]
+	if j != nil {
+		j.touched = now
+	}
+	return j
+}
+
+// evict removes jobs which were not requested for longer than TTL, running jobs are kept.
+// It must be called with s.mu held.
+func (s *Server) evict(now time.Time) {
+	if s.TTL <= 0 {
+		return
+	}
+	for id, j := range s.jobs {
+		if now.Sub(j.touched) > s.TTL && !j.running() {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (s *Server) delete(w http.ResponseWriter, j *Job) {
+	if j.running() {
+		writeError(w, http.StatusConflict, ErrJobRunning)
+		return
+	}
+	s.mu.Lock()
+	delete(s.jobs, j.id)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// upload reads a file with rows of a table, the first row of .csv and .xlsx files is a header.
+func (s *Server) upload(w http.ResponseWriter, r *http.Request, j *Job, p []string) {
+	t, err := readTable(w, r, strings.Join(p, "/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != StatusCreated {
+		writeError(w, http.StatusConflict, ErrJobStarted)
+		return
+	}
+	switch p[0] {
+	case "groups":
+		j.groups = t
+	case "priority":
+		j.priority = t
+	case "rooms":
+		j.rooms = t
+	case "students":
+		t.Name = p[1]
+		j.students[p[1]] = t
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readTable reads a table from a body of a request based on its content type.
+// The file name of a table is n with an extension of the content type.
+func readTable(w http.ResponseWriter, r *http.Request, n string) (*validation.Table, error) {
+	ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, ErrWrongContentType
+	}
+	body := http.MaxBytesReader(w, r.Body, maxUpload)
+	t := &validation.Table{File: n, Sheet: n}
+	switch ct {
+	case ContentTypeJSON:
+		t.File += ".json"
+		err = json.NewDecoder(body).Decode(&t.Rows)
+	case ContentTypeCSV:
+		t.File += ".csv"
+		t.Header = 1
+		t.Rows, err = csv.ReadFrom(body, true)
+	case ContentTypeXLSX:
+		t.File += ".xlsx"
+		t.Header = 1
+		// The whole file is needed to read a .xlsx archive
+		var b bytes.Buffer
+		if _, err = io.Copy(&b, body); err == nil {
+			t.Rows, err = xlsx.ReadFrom(&b, true)
+		}
+	default:
+		return nil, ErrWrongContentType
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", t.File, err)
+	}
+	return t, nil
+}
+
+func (s *Server) run(w http.ResponseWriter, r *http.Request, j *Job) {
+	o := &Options{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(o); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if !j.start() {
+		writeError(w, http.StatusConflict, ErrJobStarted)
+		return
+	}
+	go j.run(o)
+	writeJSON(w, http.StatusAccepted, j.view())
+}
+
+// result writes results of a job in a format passed in the format query parameter, json is the default.
+// Calendars are created for a student passed in the student parameter
+// or for a group passed in the subject and group parameters.
+func (s *Server) result(w http.ResponseWriter, r *http.Request, j *Job) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != StatusDone {
+		writeError(w, http.StatusConflict, ErrJobNotDone)
+		return
+	}
+	q := r.URL.Query()
+	var b bytes.Buffer
+	var ct string
+	var err error
+	switch q.Get("format") {
+	case "", "json":
+		ct, err = ContentTypeJSON, document.New(j.schedule, j.enrolled).Encode(&b, "json")
+	case "yaml":
+		ct, err = ContentTypeYAML, document.New(j.schedule, j.enrolled).Encode(&b, "yaml")
+	case "xlsx":
+		wb := xlsx.NewWorkbook(&b)
+		if err = results.Workbook(wb, j.schedule, j.enrolled, j.result); err == nil {
+			err = wb.Close()
+		}
+		ct = ContentTypeXLSX
+	case "ics":
+		c := j.calendar(q.Get("student"), q.Get("subject"), q.Get("group"))
+		if c == nil {
+			writeError(w, http.StatusNotFound, ErrNoTimetable)
+			return
+		}
+		ct, err = ContentTypeCalendar, c.Write(&b)
+	default:
+		writeError(w, http.StatusBadRequest, ErrWrongResultFormat)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", ct)
+	w.WriteHeader(http.StatusOK)
+	b.WriteTo(w)
+}
+
+// calendar returns a calendar of a student or a group of a subject, it returns nil when they do not exist.
+func (j *Job) calendar(st, sub, g string) *ical.Calendar {
+	if st != "" {
+		for _, s := range j.enrolled {
+			if s.Name == st {
+				return ical.ForStudent(s)
+			}
+		}
+		return nil
+	}
+	subject := j.schedule.GetSubject(sub)
+	if subject == nil {
+		return nil
+	}
+	gr := subject.GetGroup(g)
+	if gr == nil {
+		gr = subject.GetLecture(g)
+	}
+	if gr == nil {
+		return nil
+	}
+	return ical.ForGroup(sub, gr)
+}
+
+// JobView represents a status of a job returned by the API.
+type JobView struct {
+	ID          string                   `json:"id"`
+	Status      Status                   `json:"status"`
+	Error       string                   `json:"error,omitempty"`
+	Diagnostics []*validation.Diagnostic `json:"diagnostics,omitempty"`
+	Students    int                      `json:"students"`
+	Unassigned  int                      `json:"unassigned"`
+	Happiness   map[string]float64       `json:"happiness,omitempty"`
+}
+
+func (j *Job) view() *JobView {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	v := &JobView{
+		ID:          j.id,
+		Status:      j.status,
+		Error:       j.err,
+		Diagnostics: j.diagnostics,
+		Students:    len(j.students),
+	}
+	if j.result != nil {
+		v.Unassigned = len(j.result.Unassigned)
+		f := j.result.Fairness
+		v.Happiness = map[string]float64{"min": f.Min, "median": f.Median, "mean": f.Mean, "max": f.Max, "gini": f.Gini}
+	}
+	return v
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func methodNotAllowed(w http.ResponseWriter, m string) {
+	w.Header().Set("Allow", m)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed, use %s", m))
+}
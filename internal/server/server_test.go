@@ -0,0 +1,236 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/document"
+)
+
+const testGroups = `[
+	["Math", "Lecture", "T", "Monday", "8:00", "9:30", "A-1", "03-02-20", "1", "Lecture", "10"],
+	["Math", "Class", "T", "Tuesday", "10:00", "11:30", "B-2", "03-03-20", "1", "1", "1"],
+	["Math", "Class", "U", "Tuesday", "10:00", "11:30", "B-3", "03-03-20", "1", "2", "1"]
+]`
+
+func do(t *testing.T, s *Server, m, p, ct, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(m, p, strings.NewReader(body))
+	if ct != "" {
+		r.Header.Set("Content-Type", ct)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	return w
+}
+
+func newTestJob(t *testing.T, s *Server) *Job {
+	t.Helper()
+	w := do(t, s, http.MethodPost, "/jobs", "", "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /jobs code = %d, body %s", w.Code, w.Body.String())
+	}
+	v := &JobView{}
+	if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+		t.Fatalf("POST /jobs error = %v", err)
+	}
+	return s.job(v.ID)
+}
+
+func TestServer(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	p := "/jobs/" + j.id
+
+	uploads := []struct {
+		path string
+		ct   string
+		body string
+		code int
+	}{
+		{path: p + "/groups", ct: ContentTypeJSON, body: testGroups, code: http.StatusNoContent},
+		{path: p + "/students/aaa", ct: ContentTypeCSV, body: "subject,group,priority\nMath,1,1\nMath,2,2\n", code: http.StatusNoContent},
+		{path: p + "/students/bbb", ct: ContentTypeJSON, body: `[["Math", "1", "1"], ["Math", "2", "2"]]`, code: http.StatusNoContent},
+		{path: p + "/priority", ct: "text/csv; charset=utf-8", body: "name\nbbb\n", code: http.StatusNoContent},
+		{path: p + "/rooms", ct: "text/plain", body: "A-1,30", code: http.StatusBadRequest},
+		{path: p + "/teachers", ct: ContentTypeJSON, body: "[]", code: http.StatusNotFound},
+	}
+	for _, u := range uploads {
+		if w := do(t, s, http.MethodPut, u.path, u.ct, u.body); w.Code != u.code {
+			t.Errorf("PUT %s code = %d, want %d, body %s", u.path, w.Code, u.code, w.Body.String())
+		}
+	}
+
+	if w := do(t, s, http.MethodGet, p+"/result", "", ""); w.Code != http.StatusConflict {
+		t.Errorf("GET result before run code = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := do(t, s, http.MethodPost, p+"/run", ContentTypeJSON, `{"solver": "flow"}`); w.Code != http.StatusAccepted {
+		t.Fatalf("POST run code = %d, body %s", w.Code, w.Body.String())
+	}
+	<-j.done
+	if w := do(t, s, http.MethodPost, p+"/run", "", ""); w.Code != http.StatusConflict {
+		t.Errorf("POST run twice code = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if w := do(t, s, http.MethodPut, p+"/groups", ContentTypeJSON, testGroups); w.Code != http.StatusConflict {
+		t.Errorf("PUT groups after run code = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	w := do(t, s, http.MethodGet, p, "", "")
+	v := &JobView{}
+	if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+		t.Fatalf("GET job error = %v", err)
+	}
+	if v.Status != StatusDone || v.Students != 2 || v.Unassigned != 0 {
+		t.Errorf("GET job got = %+v", v)
+	}
+
+	w = do(t, s, http.MethodGet, p+"/result", "", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != ContentTypeJSON {
+		t.Fatalf("GET result code = %d, content type %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	d, err := document.Decode(w.Body, "json")
+	if err != nil {
+		t.Fatalf("GET result error = %v", err)
+	}
+	got := make(map[string]map[string]string)
+	for _, st := range d.Students {
+		got[st.Name] = st.Groups
+	}
+	want := map[string]map[string]string{
+		"aaa": {"Math": "2"},
+		"bbb": {"Math": "1"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GET result groups mismatch (-want +got):\n%s", diff)
+	}
+
+	results := []struct {
+		query string
+		code  int
+		ct    string
+		body  string
+	}{
+		{query: "?format=yaml", code: http.StatusOK, ct: ContentTypeYAML, body: "version: 1"},
+		{query: "?format=xlsx", code: http.StatusOK, ct: ContentTypeXLSX, body: "PK"},
+		{query: "?format=ics&student=aaa", code: http.StatusOK, ct: ContentTypeCalendar, body: "BEGIN:VCALENDAR"},
+		{query: "?format=ics&subject=Math&group=Lecture", code: http.StatusOK, ct: ContentTypeCalendar, body: "BEGIN:VCALENDAR"},
+		{query: "?format=ics&student=zzz", code: http.StatusNotFound, ct: ContentTypeJSON, body: ErrNoTimetable.Error()},
+		{query: "?format=pdf", code: http.StatusBadRequest, ct: ContentTypeJSON, body: ErrWrongResultFormat.Error()},
+	}
+	for _, r := range results {
+		w := do(t, s, http.MethodGet, p+"/result"+r.query, "", "")
+		if w.Code != r.code || w.Header().Get("Content-Type") != r.ct || !strings.Contains(w.Body.String(), r.body) {
+			t.Errorf("GET result%s code = %d, content type %s, want %d %s with %q", r.query, w.Code, w.Header().Get("Content-Type"), r.code, r.ct, r.body)
+		}
+	}
+}
+
+func TestServer_failed(t *testing.T) {
+	tests := []struct {
+		name    string
+		uploads map[string]string
+		want    *JobView
+	}{
+		{
+			name: "Fails without groups",
+			want: &JobView{Status: StatusFailed, Error: ErrNoGroups.Error()},
+		},
+		{
+			name: "Fails on invalid files",
+			uploads: map[string]string{
+				"groups":       testGroups,
+				"students/aaa": `[["Math", "5", "1"]]`,
+			},
+			want: &JobView{Status: StatusFailed, Error: "found 1 problems in files", Students: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New()
+			j := newTestJob(t, s)
+			for u, b := range tt.uploads {
+				do(t, s, http.MethodPut, "/jobs/"+j.id+"/"+u, ContentTypeJSON, b)
+			}
+			do(t, s, http.MethodPost, "/jobs/"+j.id+"/run", "", "")
+			<-j.done
+			got := j.view()
+			tt.want.ID = j.id
+			got.Diagnostics = nil
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer_routing(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	tests := []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{method: http.MethodGet, path: "/jobs", code: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/other", code: http.StatusNotFound},
+		{method: http.MethodGet, path: "/jobs/unknown", code: http.StatusNotFound},
+		{method: http.MethodGet, path: "/jobs/" + j.id, code: http.StatusOK},
+		{method: http.MethodPatch, path: "/jobs/" + j.id, code: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/jobs/" + j.id + "/groups", code: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/jobs/" + j.id + "/run", code: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: "/jobs/" + j.id + "/result", code: http.StatusMethodNotAllowed},
+		{method: http.MethodPut, path: "/jobs/" + j.id + "/students/", code: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if w := do(t, s, tt.method, tt.path, "", ""); w.Code != tt.code {
+			t.Errorf("%s %s code = %d, want %d", tt.method, tt.path, w.Code, tt.code)
+		}
+	}
+}
+
+func TestServer_delete(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	r := newTestJob(t, s)
+	r.status = StatusRunning
+
+	if w := do(t, s, http.MethodDelete, "/jobs/"+j.id, "", ""); w.Code != http.StatusNoContent {
+		t.Errorf("DELETE /jobs/{id} code = %d, body %s", w.Code, w.Body.String())
+	}
+	if w := do(t, s, http.MethodGet, "/jobs/"+j.id, "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET /jobs/{id} of a deleted job code = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := do(t, s, http.MethodDelete, "/jobs/"+r.id, "", ""); w.Code != http.StatusConflict {
+		t.Errorf("DELETE /jobs/{id} of a running job code = %d, want %d", w.Code, http.StatusConflict)
+	}
+}
+
+func TestServer_ttl(t *testing.T) {
+	s := New()
+	s.TTL = time.Hour
+	old, cur, run := newTestJob(t, s), newTestJob(t, s), newTestJob(t, s)
+	run.status = StatusRunning
+	s.mu.Lock()
+	old.touched = old.touched.Add(-2 * time.Hour)
+	run.touched = run.touched.Add(-2 * time.Hour)
+	s.mu.Unlock()
+
+	tests := []struct {
+		job  *Job
+		code int
+	}{
+		{job: old, code: http.StatusNotFound},
+		{job: cur, code: http.StatusOK},
+		{job: run, code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		if w := do(t, s, http.MethodGet, "/jobs/"+tt.job.id, "", ""); w.Code != tt.code {
+			t.Errorf("GET /jobs/{id} of a job touched at %v code = %d, want %d", tt.job.touched, w.Code, tt.code)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+// TradeRequest represents a student who wants to move to another group or lecture section of a subject.
+type TradeRequest struct {
+	Student string `json:"student"`
+	Subject string `json:"subject"`
+	Group   string `json:"group"`
+}
+
+// TradeReport represents executed trades, each one as a list of moves, and requests which could not be fulfilled.
+type TradeReport struct {
+	Trades  [][]*document.Event `json:"trades"`
+	Pending []*TradeRequest     `json:"pending"`
+}
+
+// trade exchanges groups of students of a done job according to a list of requests.
+// It writes the report of trading.
+func (s *Server) trade(w http.ResponseWriter, r *http.Request, j *Job) {
+	var trs []*TradeRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+	if err := json.NewDecoder(r.Body).Decode(&trs); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != StatusDone {
+		writeError(w, http.StatusConflict, ErrJobNotDone)
+		return
+	}
+	reqs := make([]*university.TradeRequest, len(trs))
+	for i, tr := range trs {
+		var st *university.Student
+		for _, e := range j.enrolled {
+			if e.Name == tr.Student {
+				st = e
+			}
+		}
+		if st == nil {
+			writeError(w, http.StatusNotFound, ErrStudentNotFound)
+			return
+		}
+		reqs[i] = &university.TradeRequest{Student: st, Subject: tr.Subject, Group: tr.Group}
+	}
+	rep, err := j.schedule.Trade(reqs)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	res := &TradeReport{Trades: [][]*document.Event{}, Pending: []*TradeRequest{}}
+	for _, t := range rep.Trades {
+		res.Trades = append(res.Trades, document.Events(t.Moves))
+	}
+	for _, p := range rep.Pending {
+		res.Pending = append(res.Pending, &TradeRequest{Student: p.Student.Name, Subject: p.Subject, Group: p.Group})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pbartkowicz/scheduler/internal/document"
+)
+
+func TestServer_trade(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	p := "/jobs/" + j.id
+	do(t, s, http.MethodPut, p+"/groups", ContentTypeJSON, testGroups)
+	do(t, s, http.MethodPut, p+"/students/aaa", ContentTypeJSON, `[["Math", "1", "1"], ["Math", "2", "2"]]`)
+	do(t, s, http.MethodPut, p+"/students/bbb", ContentTypeJSON, `[["Math", "1", "1"], ["Math", "2", "2"]]`)
+	do(t, s, http.MethodPut, p+"/priority", ContentTypeJSON, `[["bbb"]]`)
+	if w := do(t, s, http.MethodPost, p+"/trades", ContentTypeJSON, `[]`); w.Code != http.StatusConflict {
+		t.Errorf("POST trades before run code = %d, want %d", w.Code, http.StatusConflict)
+	}
+	do(t, s, http.MethodPost, p+"/run", "", "")
+	<-j.done
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{body: `[{"student": "zzz", "subject": "Math", "group": "1"}]`, code: http.StatusNotFound},
+		{body: `[{"student": "aaa", "subject": "Math", "group": "7"}]`, code: http.StatusUnprocessableEntity},
+		{body: `[{"student": "aaa"`, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if w := do(t, s, http.MethodPost, p+"/trades", ContentTypeJSON, tt.body); w.Code != tt.code {
+			t.Errorf("POST trades %s code = %d, want %d, body %s", tt.body, w.Code, tt.code, w.Body.String())
+		}
+	}
+
+	body := `[
+		{"student": "aaa", "subject": "Math", "group": "1"},
+		{"student": "bbb", "subject": "Math", "group": "2"},
+		{"student": "aaa", "subject": "Math", "group": "Lecture"}
+	]`
+	w := do(t, s, http.MethodPost, p+"/trades", ContentTypeJSON, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST trades code = %d, want %d, body %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	got := &TradeReport{}
+	if err := json.NewDecoder(w.Body).Decode(got); err != nil {
+		t.Fatalf("POST trades error = %v", err)
+	}
+	want := &TradeReport{
+		Trades: [][]*document.Event{{
+			{Type: "traded", Student: "aaa", Subject: "Math", Class: "Class", From: "2", To: "1"},
+			{Type: "traded", Student: "bbb", Subject: "Math", Class: "Class", From: "1", To: "2"},
+		}},
+		Pending: []*TradeRequest{{Student: "aaa", Subject: "Math", Group: "Lecture"}},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(document.Event{}, "Time")); diff != "" {
+		t.Errorf("POST trades mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+// ErrStudentNotFound is returned when a withdrawn student was not enrolled by a job.
+var ErrStudentNotFound = errors.New("student not found")
+
+// Withdrawal represents a student who leaves a subject after enrollment.
+type Withdrawal struct {
+	Student string `json:"student"`
+	Subject string `json:"subject"`
+}
+
+// withdraw removes a student from a subject of a done job and promotes waitlisted students to freed places.
+// It writes events caused by the withdrawal.
+func (s *Server) withdraw(w http.ResponseWriter, r *http.Request, j *Job) {
+	wd := &Withdrawal{}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+	if err := json.NewDecoder(r.Body).Decode(wd); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != StatusDone {
+		writeError(w, http.StatusConflict, ErrJobNotDone)
+		return
+	}
+	var st *university.Student
+	for _, e := range j.enrolled {
+		if e.Name == wd.Student {
+			st = e
+		}
+	}
+	if st == nil {
+		writeError(w, http.StatusNotFound, ErrStudentNotFound)
+		return
+	}
+	es, err := j.schedule.Withdraw(st, wd.Subject)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, document.Events(es))
+}
+
+// history writes all changes of students' groups of a done job.
+func (s *Server) history(w http.ResponseWriter, j *Job) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status != StatusDone {
+		writeError(w, http.StatusConflict, ErrJobNotDone)
+		return
+	}
+	es := document.Events(j.schedule.History)
+	if es == nil {
+		es = []*document.Event{}
+	}
+	writeJSON(w, http.StatusOK, es)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pbartkowicz/scheduler/internal/document"
+)
+
+func TestServer_withdraw(t *testing.T) {
+	s := New()
+	j := newTestJob(t, s)
+	p := "/jobs/" + j.id
+	do(t, s, http.MethodPut, p+"/groups", ContentTypeJSON, testGroups)
+	do(t, s, http.MethodPut, p+"/students/aaa", ContentTypeJSON, `[["Math", "1", "1"], ["Math", "2", "2"]]`)
+	do(t, s, http.MethodPut, p+"/students/bbb", ContentTypeJSON, `[["Math", "1", "1"], ["Math", "2", "2"]]`)
+	do(t, s, http.MethodPut, p+"/priority", ContentTypeJSON, `[["bbb"]]`)
+	if w := do(t, s, http.MethodPost, p+"/withdrawals", ContentTypeJSON, `{"student": "bbb", "subject": "Math"}`); w.Code != http.StatusConflict {
+		t.Errorf("POST withdrawals before run code = %d, want %d", w.Code, http.StatusConflict)
+	}
+	do(t, s, http.MethodPost, p+"/run", "", "")
+	<-j.done
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{body: `{"student": "zzz", "subject": "Math"}`, code: http.StatusNotFound},
+		{body: `{"student": "bbb", "subject": "Physics"}`, code: http.StatusUnprocessableEntity},
+		{body: `{"student": "bbb"`, code: http.StatusBadRequest},
+		{body: `{"student": "bbb", "subject": "Math"}`, code: http.StatusOK},
+		{body: `{"student": "bbb", "subject": "Math"}`, code: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		if w := do(t, s, http.MethodPost, p+"/withdrawals", ContentTypeJSON, tt.body); w.Code != tt.code {
+			t.Errorf("POST withdrawals %s code = %d, want %d, body %s", tt.body, w.Code, tt.code, w.Body.String())
+		}
+	}
+
+	w := do(t, s, http.MethodGet, p+"/history", "", "")
+	var got []*document.Event
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("GET history error = %v", err)
+	}
+	want := []*document.Event{
+		{Type: "withdrawn", Student: "bbb", Subject: "Math", Class: "Lecture", From: "Lecture"},
+		{Type: "withdrawn", Student: "bbb", Subject: "Math", Class: "Class", From: "1"},
+		{Type: "promoted", Student: "aaa", Subject: "Math", Class: "Class", From: "2", To: "1"},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(document.Event{}, "Time")); diff != "" {
+		t.Errorf("GET history mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/university"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	schedulesBucket = []byte("schedules")
+	studentsBucket  = []byte("students")
+	runsBucket      = []byte("runs")
+)
+
+var _ Store = (*BoltStore)(nil)
+
+// BoltStore is a Store which keeps all data in one file.
+// Every schedule, list of students and run is saved as a JSON value of one key.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// Open opens a store saved in file n, the file is created if it does not exist.
+// A file can be opened by one process at once.
+func Open(n string) (*BoltStore, error) {
+	db, err := bolt.Open(n, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, &Error{Op: ReadOp, Key: n, Err: err}
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{schedulesBucket, studentsBucket, runsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, &Error{Op: WriteOp, Key: n, Err: err}
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close implements Store.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// SaveSchedule implements Store.
+// Groups are saved without students, rooms and history of a schedule are not saved.
+func (s *BoltStore) SaveSchedule(n string, sch *university.Schedule) error {
+	d := document.New(sch, nil)
+	for _, sub := range d.Subjects {
+		for _, gs := range [][]*document.Group{sub.Lectures, sub.Groups} {
+			for _, g := range gs {
+				g.Students, g.PriorityStudents, g.Waitlist = nil, nil, nil
+			}
+		}
+	}
+	d.History = nil
+	return s.put(schedulesBucket, n, d)
+}
+
+// Schedule implements Store.
+func (s *BoltStore) Schedule(n string) (*university.Schedule, error) {
+	d := &document.Document{}
+	if err := s.get(schedulesBucket, n, d); err != nil {
+		return nil, err
+	}
+	sch, _, err := d.Schedule()
+	if err != nil {
+		return nil, &Error{Op: ReadOp, Key: n, Err: err}
+	}
+	return sch, nil
+}
+
+// Schedules implements Store.
+func (s *BoltStore) Schedules() ([]string, error) {
+	var res []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(schedulesBucket).ForEach(func(k, _ []byte) error {
+			res = append(res, string(k))
+			return nil
+		})
+	})
+	return res, err
+}
+
+// SaveStudents implements Store.
+// Only names, priority flags, subjects and preferences of students are saved.
+func (s *BoltStore) SaveStudents(n string, students []*university.Student) error {
+	d := document.New(&university.Schedule{}, students)
+	for i, st := range students {
+		ds := d.Students[i]
+		ds.Subjects = st.Subjects
+		ds.Happiness, ds.Groups, ds.Lectures = nil, nil, nil
+	}
+	return s.put(studentsBucket, n, d.Students)
+}
+
+// Students implements Store.
+func (s *BoltStore) Students(n string) ([]*university.Student, error) {
+	d := &document.Document{Version: document.Version}
+	if err := s.get(studentsBucket, n, &d.Students); err != nil {
+		return nil, err
+	}
+	_, students, err := d.Schedule()
+	if err != nil {
+		return nil, &Error{Op: ReadOp, Key: n, Err: err}
+	}
+	for i, ds := range d.Students {
+		if ds.Subjects == nil {
+			students[i].Subjects = nil
+		}
+	}
+	return students, nil
+}
+
+// SaveRun implements Store.
+// The ID of a run is set to the next number of a store padded with zeros, so IDs are sorted by time.
+func (s *BoltStore) SaveRun(r *Run) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(runsBucket)
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		r.ID = fmt.Sprintf("%06d", id)
+		v, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(r.ID), v)
+	})
+	if err != nil {
+		return &Error{Op: WriteOp, Key: "run", Err: err}
+	}
+	return nil
+}
+
+// Run implements Store.
+func (s *BoltStore) Run(id string) (*Run, error) {
+	r := &Run{}
+	if err := s.get(runsBucket, id, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Runs implements Store.
+// Runs are sorted by ID.
+func (s *BoltStore) Runs() ([]*Run, error) {
+	var res []*Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(runsBucket).ForEach(func(k, v []byte) error {
+			r := &Run{}
+			if err := json.Unmarshal(v, r); err != nil {
+				return &Error{Op: ReadOp, Key: string(k), Err: err}
+			}
+			res = append(res, r)
+			return nil
+		})
+	})
+	return res, err
+}
+
+func (s *BoltStore) put(b []byte, k string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return &Error{Op: WriteOp, Key: k, Err: err}
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(b).Put([]byte(k), data)
+	})
+	if err != nil {
+		return &Error{Op: WriteOp, Key: k, Err: err}
+	}
+	return nil
+}
+
+func (s *BoltStore) get(b []byte, k string, v interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(b).Get([]byte(k))
+		if data == nil {
+			return &Error{Op: ReadOp, Key: k, Err: ErrNotFound}
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return &Error{Op: ReadOp, Key: k, Err: err}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/university"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func openTestStore(t *testing.T) (*BoltStore, string) {
+	t.Helper()
+	p := "./tmp"
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		os.Mkdir(p, os.ModePerm)
+	}
+	n := filepath.Join(p, "store.db")
+	s, err := Open(n)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	return s, n
+}
+
+func TestBoltStore(t *testing.T) {
+	defer os.RemoveAll("./tmp")
+	s, n := openTestStore(t)
+	sch, students, res := newTestRun(t)
+	students[1].Subjects = []string{"Math"}
+	students[1].Priority = true
+
+	if err := s.SaveSchedule("groups", sch); err != nil {
+		t.Fatalf("SaveSchedule() error = %v", err)
+	}
+	if err := s.SaveStudents("groups", students); err != nil {
+		t.Fatalf("SaveStudents() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.SaveRun(NewRun("groups", &Parameters{Solver: "greedy", Satisfaction: "linear", Fairness: "none"}, sch, students, res)); err != nil {
+			t.Fatalf("SaveRun() error = %v", err)
+		}
+	}
+	s.Close()
+
+	// Everything is read back after a store is opened again
+	s, err := Open(n)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer s.Close()
+
+	names, err := s.Schedules()
+	if err != nil || !cmp.Equal(names, []string{"groups"}) {
+		t.Errorf("Schedules() got = %v, error %v", names, err)
+	}
+	gotSch, err := s.Schedule("groups")
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := document.New(sch, nil)
+	for _, sub := range want.Subjects {
+		for _, gs := range [][]*document.Group{sub.Lectures, sub.Groups} {
+			for _, g := range gs {
+				g.Students, g.PriorityStudents, g.Waitlist = nil, nil, nil
+			}
+		}
+	}
+	if diff := cmp.Diff(want, document.New(gotSch, nil)); diff != "" {
+		t.Errorf("Schedule() mismatch (-want +got):\n%s", diff)
+	}
+
+	gotSts, err := s.Students("groups")
+	if err != nil {
+		t.Fatalf("Students() error = %v", err)
+	}
+	wantSts := []*university.Student{
+		{
+			Name:          "aaa",
+			Preferences:   students[0].Preferences,
+			Happiness:     map[string]float64{},
+			FinalGroups:   map[string]*university.Group{},
+			FinalLectures: map[string]*university.Group{},
+		},
+		{
+			Name:          "bbb",
+			Priority:      true,
+			Subjects:      []string{"Math"},
+			Preferences:   students[1].Preferences,
+			Happiness:     map[string]float64{},
+			FinalGroups:   map[string]*university.Group{},
+			FinalLectures: map[string]*university.Group{},
+		},
+	}
+	if diff := cmp.Diff(wantSts, gotSts); diff != "" {
+		t.Errorf("Students() mismatch (-want +got):\n%s", diff)
+	}
+
+	runs, err := s.Runs()
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	var ids []string
+	for _, r := range runs {
+		ids = append(ids, r.ID)
+	}
+	if !cmp.Equal(ids, []string{"000001", "000002"}) {
+		t.Errorf("Runs() ids = %v", ids)
+	}
+	r, err := s.Run("000002")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(document.New(sch, students), r.Document); diff != "" {
+		t.Errorf("Run() document mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(res.Fairness, r.Fairness); diff != "" {
+		t.Errorf("Run() fairness mismatch (-want +got):\n%s", diff)
+	}
+	if len(Compare(runs[0], r)) != 0 {
+		t.Errorf("Compare() got changes between equal runs")
+	}
+
+	missing := map[string]func() error{
+		"000003": func() error { _, err := s.Run("000003"); return err },
+		"other":  func() error { _, err := s.Schedule("other"); return err },
+	}
+	for k, f := range missing {
+		if err, want := f(), (&Error{Op: ReadOp, Key: k, Err: ErrNotFound}); !tools.CompareErrors(err, want) {
+			t.Errorf("got error = %v, want %v", err, want)
+		}
+	}
+	if _, err := s.Students("other"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Students() error = %v, want %v", err, ErrNotFound)
+	}
+}
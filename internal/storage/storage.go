@@ -0,0 +1,206 @@
+// Package storage persists schedules, preferences of students and enrollment runs,
+// so runs can be listed, compared and reloaded after the program exits.
+// Schedules and students are stored as documents, see package document.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+var (
+	// ErrNotFound is returned when a schedule, students or a run do not exist in a store.
+	ErrNotFound = errors.New("not found")
+	// ErrUnknownStudent is returned when an unassigned student of a run does not exist in its document.
+	ErrUnknownStudent = errors.New("unknown student")
+)
+
+// Operation is a type of action which can be performed on a store.
+// It is used in error messages.
+type Operation string
+
+const (
+	// ReadOp is a read operation.
+	ReadOp Operation = "read"
+	// WriteOp is a write operation.
+	WriteOp Operation = "write"
+)
+
+// Error represents an error struct returned by this package.
+// Key - name of a schedule or ID of a run.
+type Error struct {
+	Op  Operation
+	Key string
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Op, e.Key, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Store persists schedules, students and runs.
+// Schedules and students are saved under a name, saving them again replaces the previous version.
+// Runs are saved under an ID assigned by a store, IDs of later runs are greater.
+type Store interface {
+	SaveSchedule(n string, s *university.Schedule) error
+	Schedule(n string) (*university.Schedule, error)
+	Schedules() ([]string, error)
+	SaveStudents(n string, students []*university.Student) error
+	Students(n string) ([]*university.Student, error)
+	SaveRun(r *Run) error
+	Run(id string) (*Run, error)
+	Runs() ([]*Run, error)
+	Close() error
+}
+
+// Parameters contains names of algorithms and the end date used to enroll students.
+// Baseline - ID of a run whose groups were kept by incremental enrollment or preferred with penalty Churn.
+type Parameters struct {
+	Solver       string  `json:"solver"`
+	Satisfaction string  `json:"satisfaction"`
+	Fairness     string  `json:"fairness"`
+	End          string  `json:"end,omitempty"`
+	Baseline     string  `json:"baseline,omitempty"`
+	Churn        float64 `json:"churn,omitempty"`
+	Budget       int     `json:"budget,omitempty"`
+}
+
+// Unassigned represents a student who could not be placed in a group of a subject, see university.Unassigned.
+type Unassigned struct {
+	Student string `json:"student"`
+	Subject string `json:"subject"`
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+}
+
+// Run represents one enrollment together with its parameters and outcomes.
+// ID - assigned by a store when a run is saved.
+// Schedule - name under which the schedule of a run is saved.
+// Document - schedule with enrolled students after enrollment.
+type Run struct {
+	ID         string                     `json:"id"`
+	Schedule   string                     `json:"schedule"`
+	Created    time.Time                  `json:"created"`
+	Parameters *Parameters                `json:"parameters"`
+	Document   *document.Document         `json:"document"`
+	Unassigned []*Unassigned              `json:"unassigned,omitempty"`
+	Fairness   *university.FairnessReport `json:"fairness"`
+}
+
+// NewRun creates a run from a schedule, students and the result of enrollment.
+func NewRun(n string, p *Parameters, s *university.Schedule, students []*university.Student, res *university.EnrollResult) *Run {
+	r := &Run{
+		Schedule:   n,
+		Created:    time.Now(),
+		Parameters: p,
+		Document:   document.New(s, students),
+		Fairness:   res.Fairness,
+	}
+	for _, u := range res.Unassigned {
+		r.Unassigned = append(r.Unassigned, &Unassigned{
+			Student: u.Student.Name,
+			Subject: u.Subject,
+			Type:    string(u.Type),
+			Reason:  string(u.Reason),
+		})
+	}
+	return r
+}
+
+// Restore creates the schedule, students and the result of a run.
+// It returns document.Error when the document of a run is incorrect.
+func (r *Run) Restore() (*university.Schedule, []*university.Student, *university.EnrollResult, error) {
+	s, students, err := r.Document.Schedule()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	byName := make(map[string]*university.Student)
+	for _, st := range students {
+		byName[st.Name] = st
+	}
+	res := &university.EnrollResult{Fairness: r.Fairness}
+	for _, u := range r.Unassigned {
+		st := byName[u.Student]
+		if st == nil {
+			return nil, nil, nil, &Error{Op: ReadOp, Key: r.ID, Err: fmt.Errorf("%w: %s", ErrUnknownStudent, u.Student)}
+		}
+		res.Unassigned = append(res.Unassigned, &university.Unassigned{
+			Student: st,
+			Subject: u.Subject,
+			Type:    university.ClassType(u.Type),
+			Reason:  university.UnassignedReason(u.Reason),
+		})
+	}
+	if res.Fairness == nil {
+		res.Fairness = university.NewFairnessReport(students)
+	}
+	return s, students, res, nil
+}
+
+// Change represents a student whose group or lecture section of a subject differs between two runs.
+// From and To are empty when a student was not assigned.
+type Change struct {
+	Student string
+	Subject string
+	Type    string
+	From    string
+	To      string
+}
+
+// Save creates a row with student name, subject name, type, previous group and new group.
+func (c *Change) Save() []string {
+	return []string{c.Student, c.Subject, c.Type, c.From, c.To}
+}
+
+// Compare returns changes between groups and lecture sections of students in runs a and b
+// sorted by student name, subject name and type.
+// Students who are in one run only are compared with a student without any group.
+func Compare(a, b *Run) []*Change {
+	from, to := assignments(a), assignments(b)
+	keys := make(map[[3]string]bool)
+	for _, as := range []map[[3]string]string{from, to} {
+		for k := range as {
+			keys[k] = true
+		}
+	}
+	var res []*Change
+	for k := range keys {
+		if f, t := from[k], to[k]; f != t {
+			res = append(res, &Change{Student: k[0], Subject: k[1], Type: k[2], From: f, To: t})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		x, y := res[i], res[j]
+		if x.Student != y.Student {
+			return x.Student < y.Student
+		}
+		if x.Subject != y.Subject {
+			return x.Subject < y.Subject
+		}
+		return x.Type < y.Type
+	})
+	return res
+}
+
+// assignments returns names of groups of a run by student name, subject name and type: group or lecture.
+func assignments(r *Run) map[[3]string]string {
+	res := make(map[[3]string]string)
+	for _, st := range r.Document.Students {
+		for sub, g := range st.Groups {
+			res[[3]string{st.Name, sub, "group"}] = g
+		}
+		for sub, l := range st.Lectures {
+			res[[3]string{st.Name, sub, "lecture"}] = l
+		}
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/document"
+	"github.com/pbartkowicz/scheduler/internal/university"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+// newTestRun enrolls two students in a schedule with one lecture section and two groups with one place each.
+func newTestRun(t *testing.T) (*university.Schedule, []*university.Student, *university.EnrollResult) {
+	t.Helper()
+	s, err := university.NewSchedule([][]string{
+		{"Math", "Lecture", "T", "Monday", "8:00", "9:30", "A-1", "03-02-20", "1", "Lecture", "10"},
+		{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "B-2", "03-03-20", "1", "1", "1"},
+		{"Math", "Class", "U", "Tuesday", "10:00", "11:30", "B-3", "03-03-20", "1", "2", "1"},
+	})
+	if err != nil {
+		t.Fatalf("NewSchedule() error = %v", err)
+	}
+	s.Solver, _ = university.NewSolver("greedy")
+	s.Satisfaction, _ = university.NewSatisfactionModel("linear")
+	s.Fairness, _ = university.NewFairnessPolicy("none")
+	var students []*university.Student
+	for _, n := range []string{"aaa", "bbb"} {
+		st, err := university.NewNamedStudent([][]string{{"Math", "1", "1"}, {"Math", "2", "2"}}, n)
+		if err != nil {
+			t.Fatalf("NewNamedStudent() error = %v", err)
+		}
+		students = append(students, st)
+	}
+	res, err := s.Enroll(students)
+	if err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+	return s, students, res
+}
+
+func TestError(t *testing.T) {
+	e := &Error{Op: ReadOp, Key: "000001", Err: ErrNotFound}
+	if got, want := e.Error(), "read 000001: not found"; got != want {
+		t.Errorf("Error() got = %v, want %v", got, want)
+	}
+}
+
+func TestRun_Restore(t *testing.T) {
+	s, students, res := newTestRun(t)
+	r := NewRun("groups", &Parameters{Solver: "greedy"}, s, students, res)
+	r.Unassigned = []*Unassigned{{Student: "aaa", Subject: "Physics", Type: "Class", Reason: string(university.ReasonFull)}}
+
+	rs, rsts, rres, err := r.Restore()
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if diff := cmp.Diff(document.New(s, students), document.New(rs, rsts)); diff != "" {
+		t.Errorf("Restore() mismatch (-want +got):\n%s", diff)
+	}
+	if len(rres.Unassigned) != 1 || rres.Unassigned[0].Student != rsts[0] || rres.Unassigned[0].Reason != university.ReasonFull {
+		t.Errorf("Restore() unassigned = %v", rres.Unassigned)
+	}
+	if rres.Fairness != res.Fairness {
+		t.Errorf("Restore() fairness = %v, want %v", rres.Fairness, res.Fairness)
+	}
+
+	r.ID = "000001"
+	r.Unassigned[0].Student = "zzz"
+	want := &Error{Op: ReadOp, Key: "000001", Err: fmt.Errorf("%w: zzz", ErrUnknownStudent)}
+	if _, _, _, err := r.Restore(); !tools.CompareErrors(err, want) {
+		t.Errorf("Restore() error = %v, err %v", err, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := &Run{Document: &document.Document{Students: []*document.Student{
+		{Name: "aaa", Groups: map[string]string{"Math": "1"}, Lectures: map[string]string{"Math": "Lecture"}},
+		{Name: "bbb", Groups: map[string]string{"Math": "2"}},
+	}}}
+	b := &Run{Document: &document.Document{Students: []*document.Student{
+		{Name: "aaa", Groups: map[string]string{"Math": "2"}, Lectures: map[string]string{"Math": "Lecture"}},
+		{Name: "ccc", Groups: map[string]string{"Math": "1"}},
+	}}}
+	want := []*Change{
+		{Student: "aaa", Subject: "Math", Type: "group", From: "1", To: "2"},
+		{Student: "bbb", Subject: "Math", Type: "group", From: "2"},
+		{Student: "ccc", Subject: "Math", Type: "group", To: "1"},
+	}
+	if diff := cmp.Diff(want, Compare(a, b)); diff != "" {
+		t.Errorf("Compare() mismatch (-want +got):\n%s", diff)
+	}
+	if got := Compare(a, a); len(got) != 0 {
+		t.Errorf("Compare() got = %v, want no changes", got)
+	}
+}
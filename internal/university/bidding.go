@@ -0,0 +1,148 @@
+package university
+
+import (
+	"sort"
+	"strconv"
+)
+
+// NewBiddingStudent creates a new instance of Student who spread a budget of points across groups of all subjects.
+// Priorities are derived from bids: within a subject the group with the highest bid has priority 1
+// and groups with equal bids share a priority, so the student can be enrolled by any Solver.
+// It returns StudentError when passed parameters are invalid, with ErrWrongBid when a bid is lower than 1
+// and ErrBudgetExceeded when bids sum up to more than budget.
+// Passed parameters:
+// n - student name, which is used as it is
+// bids:
+// 0 - subject name
+// 1 - group name
+// 2 - points
+func NewBiddingStudent(bids [][]string, n string, budget int) (*Student, error) {
+	s := &Student{
+		Name:          n,
+		Preferences:   make(map[SubjectGroup]int),
+		Bids:          make(map[SubjectGroup]int),
+		Happiness:     make(map[string]float64),
+		FinalGroups:   make(map[string]*Group),
+		FinalLectures: make(map[string]*Group),
+	}
+	var sum int
+	for _, b := range bids {
+		pt, err := strconv.Atoi(b[2])
+		if err != nil {
+			return nil, &StudentError{Err: err, Name: s.Name}
+		}
+		if pt < 1 {
+			return nil, &StudentError{Err: ErrWrongBid, Name: s.Name}
+		}
+		s.Bids[SubjectGroup{b[0], b[1]}] = pt
+		sum += pt
+	}
+	if sum > budget {
+		return nil, &StudentError{Err: ErrBudgetExceeded, Name: s.Name}
+	}
+	s.rankBids()
+	return s, s.validate()
+}
+
+// rankBids sets priorities of a student from their bids.
+func (s *Student) rankBids() {
+	points := make(map[string][]int)
+	for k, v := range s.Bids {
+		points[k.Subject] = append(points[k.Subject], v)
+	}
+	for _, v := range points {
+		sort.Sort(sort.Reverse(sort.IntSlice(v)))
+	}
+	for k, v := range s.Bids {
+		p := 1
+		ps := points[k.Subject]
+		for i := 1; i < len(ps) && ps[i] >= v; i++ {
+			if ps[i] != ps[i-1] {
+				p++
+			}
+		}
+		s.Preferences[k] = p
+	}
+}
+
+// BiddingSolver allocates places in groups by bids.
+// Bids of all students in all subjects are served from the highest one, ties keep the order of students,
+// and a student receives a group when it is not full, they have no group of the subject yet
+// and it does not collide with groups they already received.
+// Students who did not win any bid of a subject receive the most preferred group with their bid which has a free place,
+// they are never placed in a group without their bid.
+// Priority students are assigned to the groups they bid the most on before bids are served.
+type BiddingSolver struct{}
+
+// bid is a bid of a student on a group of a subject.
+type bid struct {
+	student *Student
+	subject *Subject
+	group   *Group
+	points  int
+}
+
+// Solve implements Solver.
+func (b *BiddingSolver) Solve(s *Schedule, students []*Student) {
+	s.assignLectures(students)
+	var bids []*bid
+	for _, st := range students {
+		for _, sub := range s.Subjects {
+			if len(sub.Groups) == 0 || !st.Enrolled(sub.Name) || !st.ranks(sub) {
+				continue
+			}
+			if st.Priority {
+				// Groups without a bid are ranked after all other groups
+				g := sub.Groups[0]
+				for _, o := range sub.Groups {
+					if rank(st, sub, o) < rank(st, sub, g) {
+						g = o
+					}
+				}
+				g.PriorityStudents = append(g.PriorityStudents, st)
+				st.FinalGroups[sub.Name] = g
+				continue
+			}
+			for _, g := range sub.Groups {
+				if p := st.Bids[SubjectGroup{sub.Name, g.Name}]; p > 0 {
+					bids = append(bids, &bid{student: st, subject: sub, group: g, points: p})
+				}
+			}
+		}
+	}
+	sort.SliceStable(bids, func(i, j int) bool {
+		return bids[i].points > bids[j].points
+	})
+	for _, bd := range bids {
+		st, sub, g := bd.student, bd.subject, bd.group
+		if st.FinalGroups[sub.Name] != nil || g.Conflicts() >= 0 || !st.CanMove(sub.Name, g) {
+			continue
+		}
+		g.Students = append(g.Students, st)
+		st.FinalGroups[sub.Name] = g
+	}
+
+	for _, sub := range s.Subjects {
+		for _, st := range students {
+			if st.Priority || len(sub.Groups) == 0 || !st.Enrolled(sub.Name) || !st.ranks(sub) || st.FinalGroups[sub.Name] != nil {
+				continue
+			}
+			var grs []*Group
+			for _, g := range sub.Groups {
+				if st.ranked(sub, g) {
+					grs = append(grs, g)
+				}
+			}
+			sort.SliceStable(grs, func(i, j int) bool {
+				return rank(st, sub, grs[i]) < rank(st, sub, grs[j])
+			})
+			for _, g := range grs {
+				if g.Conflicts() < 0 && st.CanMove(sub.Name, g) {
+					g.Students = append(g.Students, st)
+					st.FinalGroups[sub.Name] = g
+					break
+				}
+			}
+		}
+	}
+}
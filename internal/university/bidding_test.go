@@ -0,0 +1,171 @@
+package university
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestNewBiddingStudent(t *testing.T) {
+	type args struct {
+		bids   [][]string
+		n      string
+		budget int
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Student
+		err  error
+	}{
+		{
+			name: "Successfully creates student with priorities derived from bids",
+			args: args{
+				bids: [][]string{
+					{"subject1", "g1", "50"},
+					{"subject1", "g2", "20"},
+					{"subject1", "g3", "20"},
+					{"subject2", "g1", "10"},
+				},
+				n:      "student",
+				budget: 100,
+			},
+			want: &Student{
+				Name: "student",
+				Preferences: map[SubjectGroup]int{
+					{"subject1", "g1"}: 1,
+					{"subject1", "g2"}: 2,
+					{"subject1", "g3"}: 2,
+					{"subject2", "g1"}: 1,
+				},
+				Bids: map[SubjectGroup]int{
+					{"subject1", "g1"}: 50,
+					{"subject1", "g2"}: 20,
+					{"subject1", "g3"}: 20,
+					{"subject2", "g1"}: 10,
+				},
+				FinalGroups:   make(map[string]*Group),
+				FinalLectures: make(map[string]*Group),
+				Happiness:     make(map[string]float64),
+			},
+		},
+		{
+			name: "Incorrect points",
+			args: args{
+				bids:   [][]string{{"subject1", "g1", "wrong"}},
+				n:      "student",
+				budget: 100,
+			},
+			err: &StudentError{
+				Err: &strconv.NumError{
+					Func: "Atoi",
+					Num:  "wrong",
+					Err:  strconv.ErrSyntax,
+				},
+				Name: "student",
+			},
+		},
+		{
+			name: "Bid lower than 1",
+			args: args{
+				bids:   [][]string{{"subject1", "g1", "0"}},
+				n:      "student",
+				budget: 100,
+			},
+			err: &StudentError{Err: ErrWrongBid, Name: "student"},
+		},
+		{
+			name: "Bids exceed the budget",
+			args: args{
+				bids: [][]string{
+					{"subject1", "g1", "60"},
+					{"subject2", "g1", "41"},
+				},
+				n:      "student",
+				budget: 100,
+			},
+			err: &StudentError{Err: ErrBudgetExceeded, Name: "student"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBiddingStudent(tt.args.bids, tt.args.n, tt.args.budget)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("NewBiddingStudent() error = %v, err %v", err, tt.err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("NewBiddingStudent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBiddingSolver_Solve(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	group := func(n string, c int, d time.Weekday) *Group {
+		return &Group{Name: n, Type: Class, Capacity: c, Weekday: d, StartTime: ten, EndTime: ten.Add(time.Hour)}
+	}
+	s := &Schedule{
+		Subjects: []*Subject{
+			{
+				Name:   "Math",
+				Groups: []*Group{group("1", 1, time.Monday), group("2", 1, time.Tuesday), group("3", 2, time.Wednesday)},
+			},
+			{
+				Name:   "Physics",
+				Groups: []*Group{group("1", 1, time.Monday)},
+			},
+		},
+	}
+	bids := map[string][][]string{
+		"a": {{"Math", "1", "30"}, {"Math", "2", "10"}},
+		"b": {{"Math", "1", "60"}},
+		// Student c wins Physics first, so their higher bid on Math 1 collides with it
+		"c": {{"Physics", "1", "70"}, {"Math", "1", "65"}, {"Math", "2", "5"}},
+		"d": {{"Math", "3", "1"}},
+	}
+	var students []*Student
+	for _, n := range []string{"a", "b", "c", "d"} {
+		st, err := NewBiddingStudent(bids[n], n, 150)
+		if err != nil {
+			t.Fatalf("NewBiddingStudent() error = %v", err)
+		}
+		students = append(students, st)
+	}
+	students[3].Priority = true
+
+	(&BiddingSolver{}).Solve(s, students)
+	got := make(map[string]string)
+	for _, st := range students {
+		for sub, g := range st.FinalGroups {
+			if g != nil {
+				got[st.Name+" "+sub] = g.Name
+			}
+		}
+	}
+	want := map[string]string{
+		"a Math":    "2",
+		"b Math":    "1",
+		"c Physics": "1",
+		"d Math":    "3",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("BiddingSolver.Solve() mismatch (-want +got):\n%s", diff)
+	}
+	for _, sub := range s.Subjects {
+		if c := sub.Conflicts(); c > 0 {
+			t.Errorf("BiddingSolver.Solve() left %d conflicts in %s", c, sub.Name)
+		}
+	}
+	// Student c did not bid on Math 3, so they are not placed in it
+	var unassigned []string
+	for _, u := range s.unassigned(students) {
+		unassigned = append(unassigned, u.Student.Name+" "+u.Subject+" "+string(u.Reason))
+	}
+	if diff := cmp.Diff([]string{"c Math " + string(ReasonRankedFull)}, unassigned); diff != "" {
+		t.Errorf("BiddingSolver.Solve() unassigned mismatch (-want +got):\n%s", diff)
+	}
+}
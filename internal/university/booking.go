@@ -0,0 +1,49 @@
+package university
+
+// Booking represents one meeting of a group.
+// Group - the group or lecture section, Meeting - the group itself or one of its subgroups.
+type Booking struct {
+	Subject string
+	Group   *Group
+	Meeting *Group
+}
+
+// bookings returns all meetings of lecture sections and groups of a schedule.
+func (s *Schedule) bookings() (res []*Booking) {
+	for _, sub := range s.Subjects {
+		for _, gs := range [][]*Group{sub.Lectures, sub.Groups} {
+			for _, g := range gs {
+				for _, m := range g.Meetings() {
+					res = append(res, &Booking{Subject: sub.Name, Group: g, Meeting: m})
+				}
+			}
+		}
+	}
+	return
+}
+
+// collide returns the first pair of colliding meetings.
+func collide(bs []*Booking) (*Booking, *Booking) {
+	for i, a := range bs {
+		for _, b := range bs[i+1:] {
+			if a.Meeting.collide(b.Meeting) {
+				return a, b
+			}
+		}
+	}
+	return nil, nil
+}
+
+// hours returns the number of hours of meetings in an average week.
+// Meetings held every Frequency weeks count as a Frequency part of their length.
+func hours(bs []*Booking) (res float64) {
+	for _, b := range bs {
+		m := b.Meeting
+		f := m.Frequency
+		if f < 1 {
+			f = 1
+		}
+		res += float64(m.end()-minutes(m.StartTime)) / 60 / float64(f)
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package university
+
+// reduceChurn moves regular students to other groups and swaps them within subjects as long as their total happiness
+// decreased by Churn for every group which differs from the Baseline of a schedule increases, see localSearch.
+// A student leaves their group of the baseline only when it gives them more happiness than Churn.
+// A move changes only one subject, so the cost of every student is kept by index and updated only for moved students.
+func (s *Schedule) reduceChurn(students []*Student) {
+	if s.Baseline == nil {
+		return
+	}
+	prev := make(map[string]map[string]string)
+	for _, st := range s.Baseline.Students {
+		prev[st.Name] = make(map[string]string)
+		for k, g := range st.FinalGroups {
+			if g != nil {
+				prev[st.Name][k] = g.Name
+			}
+		}
+	}
+	cost := func(st *Student) (c float64) {
+		for _, sub := range s.Subjects {
+			c -= st.Happiness[sub.Name]
+			pg, ok := prev[st.Name][sub.Name]
+			if g := st.FinalGroups[sub.Name]; ok && (g == nil || g.Name != pg) {
+				c += s.Churn
+			}
+		}
+		return
+	}
+	var sts []*Student
+	for _, st := range students {
+		if !st.Priority {
+			sts = append(sts, st)
+		}
+	}
+	costs := make([]float64, len(sts))
+	for i, st := range sts {
+		costs[i] = cost(st)
+	}
+	s.localSearch(sts, func(sub *Subject, moved []int) bool {
+		var old, nc float64
+		next := make([]float64, len(moved))
+		for k, i := range moved {
+			next[k] = cost(sts[i])
+			old += costs[i]
+			nc += next[k]
+		}
+		if nc >= old {
+			return false
+		}
+		for k, i := range moved {
+			costs[i] = next[k]
+		}
+		return true
+	})
+}
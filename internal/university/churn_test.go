@@ -0,0 +1,114 @@
+package university
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchedule_EnrollBaseline(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		churn    float64
+		capacity int
+		// unranked adds student d who ranked no group
+		unranked bool
+		groups   map[string]string
+		changes  []*Change
+	}{
+		{
+			name:     "Keeps groups of the baseline when a move gives no happiness",
+			churn:    10,
+			capacity: 1,
+			groups:   map[string]string{"a": "2", "b": "1", "c": "2"},
+			changes: []*Change{
+				{Student: "c", Subject: "Math", Type: Class, To: "2", Reason: ReasonNewStudent},
+			},
+		},
+		{
+			name:     "Moves students when happiness increases more than churn",
+			churn:    10,
+			capacity: 2,
+			groups:   map[string]string{"a": "1", "b": "1", "c": "2"},
+			changes: []*Change{
+				{Student: "a", Subject: "Math", Type: Class, From: "2", To: "1", Reason: ReasonReassigned},
+				{Student: "c", Subject: "Math", Type: Class, To: "2", Reason: ReasonNewStudent},
+			},
+		},
+		{
+			name:     "Keeps groups of the baseline when happiness increases less than churn",
+			churn:    60,
+			capacity: 2,
+			groups:   map[string]string{"a": "2", "b": "1", "c": "2"},
+			changes: []*Change{
+				{Student: "c", Subject: "Math", Type: Class, To: "2", Reason: ReasonNewStudent},
+			},
+		},
+		{
+			name:     "Does not place students who ranked no group",
+			churn:    10,
+			capacity: 2,
+			unranked: true,
+			groups:   map[string]string{"a": "1", "b": "1", "c": "2"},
+			changes: []*Change{
+				{Student: "a", Subject: "Math", Type: Class, From: "2", To: "1", Reason: ReasonReassigned},
+				{Student: "c", Subject: "Math", Type: Class, To: "2", Reason: ReasonNewStudent},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newSubject := func() *Subject {
+				return &Subject{
+					Name: "Math",
+					Groups: []*Group{
+						{Name: "1", Type: Class, Capacity: tt.capacity, Weekday: time.Monday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+						{Name: "2", Type: Class, Capacity: 2, Weekday: time.Tuesday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+					},
+				}
+			}
+			bsub := newSubject()
+			base := []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2}),
+			}
+			base[0].place("Math", bsub.Groups[1])
+			base[1].place("Math", bsub.Groups[0])
+
+			students := []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2}),
+				newTestStudent("c", map[string]int{"2": 1}),
+			}
+			if tt.unranked {
+				d := newTestStudent("d", nil)
+				d.Subjects = []string{"Math"}
+				students = append(students, d)
+			}
+			s := &Schedule{
+				Subjects: []*Subject{newSubject()},
+				Solver:   &FlowSolver{},
+				Baseline: &Baseline{Schedule: &Schedule{Subjects: []*Subject{bsub}}, Students: base},
+				Churn:    tt.churn,
+			}
+			res, err := s.Enroll(students)
+			if err != nil {
+				t.Fatalf("Schedule.Enroll() error = %v", err)
+			}
+			groups := make(map[string]string)
+			for _, st := range students {
+				if g := st.FinalGroups["Math"]; g != nil {
+					groups[st.Name] = g.Name
+				}
+			}
+			if diff := cmp.Diff(tt.groups, groups); diff != "" {
+				t.Errorf("Schedule.Enroll() groups mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.changes, res.Changes); diff != "" {
+				t.Errorf("Schedule.Enroll() changes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,17 +1,165 @@
 package university
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 )
 
-// Enroll is used to assign students and resolve conflicts in schedule
-func (s *Schedule) Enroll(students []*Student) {
-	s.assign(students)
-	// Sort subjects by number of conflicts
-	sort.Sort(s)
-	s.resolve(students)
+// ErrCapacityExceeded is returned when a group has more students than its capacity after enrollment.
+var ErrCapacityExceeded = errors.New("number of students exceeds capacity of a group")
+
+// EnrollError represents an error struct returned when enrollment fails.
+type EnrollError struct {
+	Subject string
+	Group   string
+	Err     error
+}
+
+func (e *EnrollError) Error() string {
+	return fmt.Sprintf("failed to enroll students [%s %s]: %s", e.Subject, e.Group, e.Err.Error())
+}
+
+// UnassignedReason describes why a student could not be assigned to any group of a subject.
+type UnassignedReason string
+
+const (
+	// ReasonFull - all groups which do not collide with student's timetable are full.
+	ReasonFull UnassignedReason = "all groups are full"
+	// ReasonRankedFull - all groups which student ranked are full or collide with their timetable,
+	// but some group which they did not rank has a free place.
+	ReasonRankedFull UnassignedReason = "all ranked groups are full or collide with other groups"
+	// ReasonCollision - every group collides with student's timetable.
+	ReasonCollision UnassignedReason = "every group collides with other groups"
+	// ReasonNoPreference - student did not give any preference for a subject.
+	ReasonNoPreference UnassignedReason = "no preference given"
+)
+
+// Unassigned represents a student who could not be placed in any group or lecture section of a subject.
+type Unassigned struct {
+	Student *Student
+	Subject string
+	Type    ClassType
+	Reason  UnassignedReason
+}
+
+// EnrollResult represents the outcome of enrollment.
+// Unassigned - students who could not be placed in any group of a subject.
+// Fairness - distribution of happiness of regular students.
+// Changes - students whose groups differ from a baseline, set only when a baseline is used.
+type EnrollResult struct {
+	Unassigned []*Unassigned
+	Fairness   *FairnessReport
+	Changes    []*Change
+}
+
+// Save creates a slice with students who could not be placed in groups.
+// Each row contains student name, subject name, class type and reason.
+func (r *EnrollResult) Save() [][]string {
+	res := make([][]string, len(r.Unassigned))
+	for i, u := range r.Unassigned {
+		res[i] = []string{u.Student.Name, u.Subject, string(u.Type), string(u.Reason)}
+	}
+	return res
+}
+
+// SaveChanges creates a slice with students whose groups differ from a baseline, see Change.Save.
+func (r *EnrollResult) SaveChanges() [][]string {
+	res := make([][]string, len(r.Changes))
+	for i, c := range r.Changes {
+		res[i] = c.Save()
+	}
+	return res
+}
+
+// Enroll is used to assign students and resolve conflicts in schedule.
+// It uses the Solver of a schedule, GreedySolver is used when it is not set.
+// Happiness of students is calculated with the Satisfaction model of a schedule and distributed according to its Fairness policy.
+// Students are enrolled only in subjects which they chose, see Student.Enrolled.
+// When a Baseline is set, students keep their groups of the baseline unless a move gives them more happiness than Churn
+// and Changes of the result contain every student whose group differs from the baseline.
+// Afterwards waitlists of groups are created, see Group.Waitlist.
+// It returns StudentError when a student chose a subject or a group which does not exist
+// and EnrollError when capacity of any group is exceeded, e.g. by priority students.
+func (s *Schedule) Enroll(students []*Student) (*EnrollResult, error) {
+	if err := s.ValidateStudents(students); err != nil {
+		return nil, err
+	}
+	sv := s.Solver
+	if sv == nil {
+		sv = &GreedySolver{}
+	}
+	sv.Solve(s, students)
+	s.resolveLectures(students)
+	s.calculateHappiness(students)
+	s.improveFairness(students)
+	s.reduceChurn(students)
+	s.buildWaitlists(students)
 	printHappiness(students)
+	res, err := s.result(students)
+	if res != nil && s.Baseline != nil {
+		res.Changes = s.changes(s.Baseline, students, nil)
+	}
+	return res, err
+}
+
+// calculateHappiness calculates happiness of students in all subjects with groups in which they are enrolled.
+func (s *Schedule) calculateHappiness(students []*Student) {
+	for _, sub := range s.Subjects {
+		if len(sub.Groups) == 0 {
+			continue
+		}
+		for _, st := range students {
+			if st.Enrolled(sub.Name) {
+				st.CalculateHappiness(s.satisfaction(), sub)
+			}
+		}
+	}
+}
+
+// result creates the result of enrollment.
+// It returns EnrollError together with the result when capacity of any group is exceeded.
+func (s *Schedule) result(students []*Student) (*EnrollResult, error) {
+	res := &EnrollResult{
+		Unassigned: s.unassigned(students),
+		Fairness:   NewFairnessReport(students),
+	}
+	for _, sub := range s.Subjects {
+		for _, g := range sub.Groups {
+			if g.Conflicts() > 0 {
+				return res, &EnrollError{Subject: sub.Name, Group: g.Name, Err: ErrCapacityExceeded}
+			}
+		}
+	}
+	return res, nil
+}
+
+// unassigned returns students who were not placed in any group or lecture section of a subject.
+func (s *Schedule) unassigned(students []*Student) (res []*Unassigned) {
+	for _, sub := range s.Subjects {
+		for _, st := range students {
+			if !st.Enrolled(sub.Name) {
+				continue
+			}
+			if len(sub.Lectures) != 0 && st.FinalLectures[sub.Name] == nil {
+				res = append(res, &Unassigned{
+					Student: st,
+					Subject: sub.Name,
+					Type:    Lecture,
+					Reason:  st.unassignedReason(sub, sub.Lectures),
+				})
+			}
+			if len(sub.Groups) != 0 && st.FinalGroups[sub.Name] == nil {
+				res = append(res, &Unassigned{
+					Student: st,
+					Subject: sub.Name,
+					Type:    sub.Groups[0].Type,
+					Reason:  st.unassignedReason(sub, sub.Groups),
+				})
+			}
+		}
+	}
+	return
 }
 
 func printHappiness(students []*Student) {
@@ -26,25 +174,121 @@ func printHappiness(students []*Student) {
 	fmt.Printf("\nStudents' happiness: %.2f\n", happy/float64(stLen))
 }
 
+// assignLectures assigns students to lecture sections.
+// Priority students are assigned first, then every student receives the most preferred section
+// which is not full and does not collide with their other lectures.
+func (s *Schedule) assignLectures(students []*Student) {
+	var sts []*Student
+	for _, st := range students {
+		if st.Priority {
+			sts = append(sts, st)
+		}
+	}
+	for _, st := range students {
+		if !st.Priority {
+			sts = append(sts, st)
+		}
+	}
+	for _, sub := range s.Subjects {
+		if len(sub.Lectures) == 0 {
+			continue
+		}
+		for _, st := range sts {
+			if !st.Enrolled(sub.Name) || st.FinalLectures[sub.Name] != nil {
+				continue
+			}
+			if st.FinalLectures == nil {
+				st.FinalLectures = make(map[string]*Group)
+			}
+			ls := make([]*Group, len(sub.Lectures))
+			copy(ls, sub.Lectures)
+			sort.SliceStable(ls, func(i, j int) bool {
+				return lectureRank(st, sub, ls[i]) < lectureRank(st, sub, ls[j])
+			})
+			for _, l := range ls {
+				if l.Conflicts() >= 0 || !st.canAttendLecture(sub.Name, l) {
+					continue
+				}
+				if st.Priority {
+					l.PriorityStudents = append(l.PriorityStudents, st)
+				} else {
+					l.Students = append(l.Students, st)
+				}
+				st.FinalLectures[sub.Name] = l
+				break
+			}
+		}
+	}
+}
+
+// resolveLectures moves students whose lecture section collides with one of their groups to the most preferred section
+// which has a free place and does not collide with their timetable. Students without such a section are left without it.
+// Lecture sections are assigned before groups, so groups chosen by a solver may collide with them.
+func (s *Schedule) resolveLectures(students []*Student) {
+	for _, sub := range s.Subjects {
+		for _, st := range students {
+			l := st.FinalLectures[sub.Name]
+			if l == nil || st.canAttendLecture(sub.Name, l) {
+				continue
+			}
+			l.leave(st)
+			delete(st.FinalLectures, sub.Name)
+			ls := make([]*Group, len(sub.Lectures))
+			copy(ls, sub.Lectures)
+			sort.SliceStable(ls, func(i, j int) bool {
+				return lectureRank(st, sub, ls[i]) < lectureRank(st, sub, ls[j])
+			})
+			for _, o := range ls {
+				if o.Conflicts() < 0 && st.canAttendLecture(sub.Name, o) {
+					st.place(sub.Name, o)
+					break
+				}
+			}
+		}
+	}
+}
+
+// lectureRank returns the priority which a student set to a lecture section.
+// Sections without a priority are ranked after all sections of a subject.
+func lectureRank(st *Student, sub *Subject, l *Group) int {
+	if p := st.Preferences[SubjectGroup{sub.Name, l.Name}]; p > 0 {
+		return p
+	}
+	return len(sub.Lectures) + 1
+}
+
 // assign students to preferred groups
 func (s *Schedule) assign(students []*Student) {
+	s.assignLectures(students)
 	for _, st := range students {
 		for _, sub := range s.Subjects {
-			for _, l := range sub.Lectures {
-				l.Students = append(l.Students, st)
-			}
 			gns := sub.GetGroupsNames()
 			// Subject has no groups
-			if len(gns) == 0 {
+			if len(gns) == 0 || !st.Enrolled(sub.Name) {
+				continue
+			}
+			// Groups which collide with lecture sections of a student or with groups of other subjects are not taken into account
+			var free []string
+			for _, n := range gns {
+				if st.CanMove(sub.Name, sub.GetGroup(n)) {
+					free = append(free, n)
+				}
+			}
+			if len(free) == 0 {
 				continue
 			}
-			prefGroup := st.GetPreferredGroup(sub.Name, gns)
+			prefGroup := st.GetPreferredGroup(sub.Name, free)
 			g := sub.GetGroup(prefGroup)
+			// Student did not rank any group which they can attend
+			if g == nil {
+				continue
+			}
 			if st.Priority {
 				g.PriorityStudents = append(g.PriorityStudents, st)
 			} else {
 				g.Students = append(g.Students, st)
 			}
+			st.FinalGroups[sub.Name] = g
 			st.Happiness[sub.Name] = 100.0
 		}
 	}
@@ -86,11 +330,23 @@ func (s *Schedule) resolve(students []*Student) {
 					continue
 				}
 
-				sg, mSgs = pop(mSgs)
-				sg.Group.Students = append(sg.Group.Students, sg.Student)
-				g.RemoveStudent(sg.Student)
-				// Change student happiness
-				sg.Student.CalculateHappiness(sub.Name)
+				if len(mSgs) != 0 {
+					sg, mSgs = pop(mSgs)
+					sg.Group.Students = append(sg.Group.Students, sg.Student)
+					g.RemoveStudent(sg.Student)
+					// Change student happiness
+					sg.Student.FinalGroups[sub.Name] = sg.Group
+					sg.Student.CalculateHappiness(s.satisfaction(), sub)
+					continue
+				}
+
+				// Nobody can be moved, the least happy student is left without a group
+				if len(g.Students) == 0 {
+					break
+				}
+				st := g.Students[len(g.Students)-1]
+				g.RemoveStudent(st)
+				st.Happiness[sub.Name] = 0
 			}
 		}
 		// Set final groups for this subject
@@ -107,7 +363,11 @@ type StudentGroup struct {
 }
 
 // getStudents returns students who can be moved to other groups and like or doesn't like being moved.
+// The last group of a subject has no next group, so nobody can be moved from it.
 func getStudents(i int, likes bool, s *Subject, students []*Student) (sgs []*StudentGroup) {
+	if i+1 >= len(s.Groups) {
+		return
+	}
 	for _, st := range students {
 		if likes && st.Likes(s.Name, s.Groups[i+1].Name) && st.CanMove(s.Name, s.Groups[i+1]) {
 			sgs = append(sgs, &StudentGroup{Student: st, Group: s.Groups[i+1]})
@@ -2,8 +2,10 @@ package university
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
 )
 
 func Test_pop(t *testing.T) {
@@ -299,3 +301,363 @@ func Test_getStudents(t *testing.T) {
 		})
 	}
 }
+
+func TestEnrollResult_Save(t *testing.T) {
+	r := &EnrollResult{
+		Unassigned: []*Unassigned{
+			{
+				Student: &Student{Name: "a"},
+				Subject: "Math",
+				Type:    Class,
+				Reason:  ReasonFull,
+			},
+			{
+				Student: &Student{Name: "b"},
+				Subject: "Programming",
+				Type:    Lecture,
+				Reason:  ReasonNoPreference,
+			},
+		},
+	}
+	want := [][]string{
+		{"a", "Math", "Class", "all groups are full"},
+		{"b", "Programming", "Lecture", "no preference given"},
+	}
+	if got := r.Save(); !cmp.Equal(got, want) {
+		t.Errorf("EnrollResult.Save() = %v, want %v", got, want)
+	}
+}
+
+func TestSchedule_Enroll(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		solver     Solver
+		fairness   FairnessPolicy
+		groups     []*Group
+		physics    []*Group
+		students   []*Student
+		unassigned map[string]UnassignedReason
+		err        error
+	}{
+		{
+			name:   "Leaves students from the last overcrowded group without a group",
+			solver: &GreedySolver{},
+			groups: []*Group{
+				{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				newTestStudent("b", map[string]int{"1": 1}),
+				{
+					Name:        "c",
+					Subjects:    []string{"Math"},
+					FinalGroups: make(map[string]*Group),
+					Happiness:   make(map[string]float64),
+				},
+				newTestStudent("d", nil),
+			},
+			unassigned: map[string]UnassignedReason{
+				"b": ReasonFull,
+				"c": ReasonNoPreference,
+			},
+		},
+		{
+			name:   "Does not assign students who ranked no group with the greedy solver",
+			solver: &GreedySolver{},
+			groups: []*Group{
+				{Name: "1", Capacity: 2, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				{
+					Name:        "b",
+					Subjects:    []string{"Math"},
+					FinalGroups: make(map[string]*Group),
+					Happiness:   make(map[string]float64),
+				},
+			},
+			unassigned: map[string]UnassignedReason{
+				"b": ReasonNoPreference,
+			},
+		},
+		{
+			name:   "Does not assign colliding groups of different subjects with the greedy solver",
+			solver: &GreedySolver{},
+			groups: []*Group{
+				{Name: "1", Capacity: 2, Weekday: time.Monday, StartTime: ten},
+			},
+			physics: []*Group{
+				{Name: "1", Capacity: 2, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				{
+					Name: "a",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}:    1,
+						{"Physics", "1"}: 1,
+					},
+					FinalGroups: make(map[string]*Group),
+					Happiness:   make(map[string]float64),
+				},
+			},
+			unassigned: map[string]UnassignedReason{
+				"a": ReasonCollision,
+			},
+		},
+		{
+			name:   "Does not assign students who ranked no group with the flow solver",
+			solver: &FlowSolver{},
+			groups: []*Group{
+				{Name: "1", Capacity: 2, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				{
+					Name:        "b",
+					Subjects:    []string{"Math"},
+					FinalGroups: make(map[string]*Group),
+					Happiness:   make(map[string]float64),
+				},
+			},
+			unassigned: map[string]UnassignedReason{
+				"b": ReasonNoPreference,
+			},
+		},
+		{
+			name:   "Does not assign students who ranked no group with the search solver",
+			solver: &SearchSolver{},
+			groups: []*Group{
+				{Name: "1", Capacity: 2, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				{
+					Name:        "b",
+					Subjects:    []string{"Math"},
+					FinalGroups: make(map[string]*Group),
+					Happiness:   make(map[string]float64),
+				},
+			},
+			unassigned: map[string]UnassignedReason{
+				"b": ReasonNoPreference,
+			},
+		},
+		{
+			name:     "Does not assign students who ranked no group with the maxmin fairness policy",
+			solver:   &FlowSolver{},
+			fairness: FairnessMaxMin,
+			groups: []*Group{
+				{Name: "1", Capacity: 2, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				{
+					Name:        "b",
+					Subjects:    []string{"Math"},
+					FinalGroups: make(map[string]*Group),
+					Happiness:   make(map[string]float64),
+				},
+			},
+			unassigned: map[string]UnassignedReason{
+				"b": ReasonNoPreference,
+			},
+		},
+		{
+			name:     "Does not assign students who ranked no group with the leximin fairness policy",
+			solver:   &FlowSolver{},
+			fairness: FairnessLeximin,
+			groups: []*Group{
+				{Name: "1", Capacity: 2, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				{
+					Name:        "b",
+					Subjects:    []string{"Math"},
+					FinalGroups: make(map[string]*Group),
+					Happiness:   make(map[string]float64),
+				},
+			},
+			unassigned: map[string]UnassignedReason{
+				"b": ReasonNoPreference,
+			},
+		},
+		{
+			name:   "Fails when priority students exceed capacity",
+			solver: &FlowSolver{},
+			groups: []*Group{
+				{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				newTestStudent("b", map[string]int{"1": 1}),
+			},
+			unassigned: map[string]UnassignedReason{},
+			err: &EnrollError{
+				Subject: "Math",
+				Group:   "1",
+				Err:     ErrCapacityExceeded,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err != nil {
+				for _, st := range tt.students {
+					st.Priority = true
+				}
+			}
+			s := &Schedule{
+				Subjects: []*Subject{
+					{
+						Name:   "Math",
+						Groups: tt.groups,
+					},
+				},
+				Solver:   tt.solver,
+				Fairness: tt.fairness,
+			}
+			if tt.physics != nil {
+				s.Subjects = append(s.Subjects, &Subject{Name: "Physics", Groups: tt.physics})
+			}
+			res, err := s.Enroll(tt.students)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("Schedule.Enroll() error = %v, err %v", err, tt.err)
+			}
+			got := make(map[string]UnassignedReason)
+			for _, u := range res.Unassigned {
+				got[u.Student.Name] = u.Reason
+			}
+			if !cmp.Equal(got, tt.unassigned) {
+				t.Errorf("Schedule.Enroll() unassigned = %v, want %v", got, tt.unassigned)
+			}
+		})
+	}
+}
+
+func TestSchedule_assignLectures(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	s := &Schedule{
+		Subjects: []*Subject{
+			{
+				Name: "Math",
+				Lectures: []*Group{
+					{Name: "Lecture A", Type: Lecture, Capacity: 1, Weekday: time.Monday, StartTime: ten},
+					{Name: "Lecture B", Type: Lecture, Capacity: 2, Weekday: time.Tuesday, StartTime: ten},
+				},
+			},
+			{
+				Name: "Physics",
+				Lectures: []*Group{
+					{Name: "Lecture 1", Type: Lecture, Capacity: 3, Weekday: time.Tuesday, StartTime: ten},
+					{Name: "Lecture 2", Type: Lecture, Capacity: 3, Weekday: time.Wednesday, StartTime: ten},
+				},
+			},
+		},
+	}
+	students := []*Student{
+		{
+			Name:     "a",
+			Subjects: []string{"Math", "Physics"},
+			Preferences: map[SubjectGroup]int{
+				{"Math", "Lecture A"}: 1,
+				{"Math", "Lecture B"}: 2,
+			},
+		},
+		{
+			Name:     "b",
+			Priority: true,
+			Subjects: []string{"Math", "Physics"},
+			Preferences: map[SubjectGroup]int{
+				{"Math", "Lecture A"}: 1,
+				{"Math", "Lecture B"}: 2,
+			},
+		},
+	}
+	s.assignLectures(students)
+	want := map[string]map[string]*Group{
+		"a": {
+			"Math":    s.Subjects[0].Lectures[1],
+			"Physics": s.Subjects[1].Lectures[1],
+		},
+		"b": {
+			"Math":    s.Subjects[0].Lectures[0],
+			"Physics": s.Subjects[1].Lectures[0],
+		},
+	}
+	for _, st := range students {
+		for sub, l := range want[st.Name] {
+			if st.FinalLectures[sub] != l {
+				t.Errorf("Schedule.assignLectures() student %s, subject %s got = %v, want %v", st.Name, sub, st.FinalLectures[sub], l)
+			}
+		}
+	}
+}
+
+func TestSchedule_resolveLectures(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	hour := ten.Add(time.Hour)
+	math := &Subject{
+		Name: "Math",
+		Lectures: []*Group{
+			{Name: "Lecture A", Type: Lecture, Capacity: 2, Weekday: time.Monday, StartTime: ten, EndTime: hour},
+			{Name: "Lecture B", Type: Lecture, Capacity: 1, Weekday: time.Tuesday, StartTime: ten, EndTime: hour},
+		},
+	}
+	physics := &Group{Name: "1", Type: Class, Capacity: 2, Weekday: time.Monday, StartTime: ten, EndTime: hour}
+	s := &Schedule{Subjects: []*Subject{math, {Name: "Physics", Groups: []*Group{physics}}}}
+	var students []*Student
+	for _, n := range []string{"a", "b"} {
+		st := &Student{
+			Name:          n,
+			Preferences:   map[SubjectGroup]int{{"Math", "Lecture A"}: 1, {"Math", "Lecture B"}: 2, {"Physics", "1"}: 1},
+			FinalGroups:   map[string]*Group{"Physics": physics},
+			FinalLectures: map[string]*Group{"Math": math.Lectures[0]},
+		}
+		math.Lectures[0].Students = append(math.Lectures[0].Students, st)
+		physics.Students = append(physics.Students, st)
+		students = append(students, st)
+	}
+	s.resolveLectures(students)
+	want := map[string]string{"a": "Lecture B"}
+	got := make(map[string]string)
+	for _, st := range students {
+		if l := st.FinalLectures["Math"]; l != nil {
+			got[st.Name] = l.Name
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Schedule.resolveLectures() mismatch (-want +got):\n%s", diff)
+	}
+	if n := len(math.Lectures[0].Students); n != 0 {
+		t.Errorf("Schedule.resolveLectures() left %d students in a colliding lecture section", n)
+	}
+}
+
+func TestGreedySolver_SolveLectureCollision(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	hour := ten.Add(time.Hour)
+	s := &Schedule{
+		Subjects: []*Subject{
+			{
+				Name:     "Math",
+				Lectures: []*Group{{Name: "Lecture", Type: Lecture, Capacity: 10, Weekday: time.Monday, StartTime: ten, EndTime: hour}},
+				Groups: []*Group{
+					{Name: "1", Type: Class, Capacity: 10, Weekday: time.Monday, StartTime: ten, EndTime: hour},
+					{Name: "2", Type: Class, Capacity: 10, Weekday: time.Tuesday, StartTime: ten, EndTime: hour},
+				},
+			},
+		},
+	}
+	st := newTestStudent("a", map[string]int{"1": 1, "2": 2})
+	if _, err := s.Enroll([]*Student{st}); err != nil {
+		t.Fatalf("Schedule.Enroll() error = %v", err)
+	}
+	if g := st.FinalGroups["Math"]; g == nil || g.Name != "2" {
+		t.Errorf("Schedule.Enroll() group = %v, want 2", g)
+	}
+	if l := st.FinalLectures["Math"]; l == nil || l.Collide(st.FinalGroups["Math"]) {
+		t.Errorf("Schedule.Enroll() lecture section = %v collides with a group", l)
+	}
+}
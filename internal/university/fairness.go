@@ -0,0 +1,320 @@
+package university
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrWrongFairnessPolicy is returned when a passed fairness policy name is incorrect.
+var ErrWrongFairnessPolicy = errors.New("incorrect fairness policy, available policies: none, maxmin, leximin")
+
+// FairnessPolicy defines how happiness is distributed between students after solving.
+type FairnessPolicy string
+
+const (
+	// FairnessNone - the result of a solver is not changed.
+	FairnessNone FairnessPolicy = "none"
+	// FairnessMaxMin - students are moved between groups as long as the minimum happiness increases.
+	// Ties are broken by the total happiness.
+	FairnessMaxMin FairnessPolicy = "maxmin"
+	// FairnessLeximin - students are moved between groups as long as happiness sorted in ascending order
+	// increases lexicographically, so the least happy students are improved first.
+	FairnessLeximin FairnessPolicy = "leximin"
+)
+
+var policies = map[string]FairnessPolicy{
+	"none":    FairnessNone,
+	"maxmin":  FairnessMaxMin,
+	"leximin": FairnessLeximin,
+}
+
+// NewFairnessPolicy returns a fairness policy with a passed name.
+// It returns ErrWrongFairnessPolicy when the name is incorrect.
+func NewFairnessPolicy(n string) (FairnessPolicy, error) {
+	p, ok := policies[n]
+	if !ok {
+		return "", ErrWrongFairnessPolicy
+	}
+	return p, nil
+}
+
+const (
+	// localSearchPasses is the maximum number of improvement passes of localSearch.
+	localSearchPasses = 50
+	// localSearchSwapCandidates is the maximum number of students with whom a student tries to swap groups
+	// of a subject in one pass of localSearch.
+	localSearchSwapCandidates = 100
+)
+
+// improveFairness moves regular students to other groups and swaps them within subjects
+// as long as the distribution of happiness improves according to a fairness policy, see localSearch.
+// Happiness of all students is kept sorted, so every move is evaluated only by the values which it changes.
+// Happiness of every student is kept by index, so the values which a move replaces are exactly the ones in the sorted slice.
+func (s *Schedule) improveFairness(students []*Student) {
+	if s.Fairness == "" || s.Fairness == FairnessNone {
+		return
+	}
+	var sts []*Student
+	for _, st := range students {
+		if !st.Priority {
+			sts = append(sts, st)
+		}
+	}
+	vals := make([]float64, len(sts))
+	for i, st := range sts {
+		vals[i] = st.GetHappiness()
+	}
+	h := append([]float64{}, vals...)
+	sort.Float64s(h)
+	s.localSearch(sts, func(sub *Subject, moved []int) bool {
+		old, nh := make([]float64, len(moved)), make([]float64, len(moved))
+		for k, i := range moved {
+			old[k], nh[k] = vals[i], sts[i].GetHappiness()
+		}
+		if !s.Fairness.improves(h, old, nh) {
+			return false
+		}
+		h = replace(h, old, nh)
+		for k, i := range moved {
+			vals[i] = nh[k]
+		}
+		return true
+	})
+}
+
+// localSearch moves students sts to free groups and swaps them within subjects.
+// After every move accept is called with the subject and indexes of moved students in sts,
+// the move is kept when it returns true and undone otherwise.
+// Students are moved only to groups which they ranked, capacities of groups are never exceeded
+// and moved students' timetables do not collide.
+// It stops when no move was kept in a pass or after localSearchPasses passes;
+// in one pass a student tries to swap groups of a subject with at most localSearchSwapCandidates students.
+func (s *Schedule) localSearch(sts []*Student, accept func(sub *Subject, moved []int) bool) {
+	for pass := 0; pass < localSearchPasses; pass++ {
+		improved := false
+		for _, sub := range s.Subjects {
+			for i, st := range sts {
+				if !st.Enrolled(sub.Name) || !st.ranks(sub) {
+					continue
+				}
+				for _, g := range sub.Groups {
+					cur := st.FinalGroups[sub.Name]
+					if g == cur || !st.ranked(sub, g) || g.Conflicts() >= 0 || !st.CanMove(sub.Name, g) {
+						continue
+					}
+					s.move(st, sub, g)
+					if accept(sub, []int{i}) {
+						improved = true
+						continue
+					}
+					s.move(st, sub, cur)
+				}
+			}
+			for i, a := range sts {
+				var tried int
+				for j := i + 1; j < len(sts); j++ {
+					if tried == localSearchSwapCandidates {
+						break
+					}
+					b := sts[j]
+					ga, gb := a.FinalGroups[sub.Name], b.FinalGroups[sub.Name]
+					if ga == nil || gb == nil || ga == gb || !a.ranked(sub, gb) || !b.ranked(sub, ga) ||
+						!a.CanMove(sub.Name, gb) || !b.CanMove(sub.Name, ga) {
+						continue
+					}
+					tried++
+					s.move(a, sub, gb)
+					s.move(b, sub, ga)
+					if accept(sub, []int{i, j}) {
+						improved = true
+						continue
+					}
+					s.move(a, sub, ga)
+					s.move(b, sub, gb)
+				}
+			}
+		}
+		if !improved {
+			return
+		}
+	}
+}
+
+// move assigns a student to a group of a subject and updates their happiness.
+// If the group is nil, the student is left without a group.
+func (s *Schedule) move(st *Student, sub *Subject, g *Group) {
+	if cur := st.FinalGroups[sub.Name]; cur != nil {
+		cur.RemoveStudent(st)
+	}
+	if g != nil {
+		g.Students = append(g.Students, st)
+	}
+	st.FinalGroups[sub.Name] = g
+	st.CalculateHappiness(s.satisfaction(), sub)
+}
+
+// improves checks if replacing values prev with values next in happiness h improves it according to a policy.
+// h has to be sorted in ascending order and contain prev.
+func (p FairnessPolicy) improves(h, prev, next []float64) bool {
+	switch p {
+	case FairnessMaxMin:
+		// The minimum of the values which are kept is the first value of h which is not replaced
+		min := math.Inf(1)
+		removed := append([]float64{}, prev...)
+		for _, v := range h {
+			if i := index(removed, v); i >= 0 {
+				removed = append(removed[:i], removed[i+1:]...)
+				continue
+			}
+			min = v
+			break
+		}
+		for _, v := range next {
+			min = math.Min(min, v)
+		}
+		if len(h) == 0 || min != h[0] {
+			return len(h) > 0 && min > h[0]
+		}
+		return sum(next) > sum(prev)
+	case FairnessLeximin:
+		// Values which are both removed and added cancel out, the smallest of the remaining ones decides:
+		// happiness improves when it was removed
+		o, n := append([]float64{}, prev...), append([]float64{}, next...)
+		sort.Float64s(o)
+		sort.Float64s(n)
+		for i, j := 0, 0; i < len(o) && j < len(n); {
+			switch {
+			case o[i] == n[j]:
+				i++
+				j++
+			case o[i] < n[j]:
+				return true
+			default:
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// replace removes values prev from sorted happiness h and inserts values next keeping the order.
+// Values prev have to be taken from h, they are matched exactly.
+func replace(h, prev, next []float64) []float64 {
+	for _, v := range prev {
+		if i := sort.SearchFloat64s(h, v); i < len(h) && h[i] == v {
+			h = append(h[:i], h[i+1:]...)
+		}
+	}
+	for _, v := range next {
+		i := sort.SearchFloat64s(h, v)
+		h = append(h, 0)
+		copy(h[i+1:], h[i:])
+		h[i] = v
+	}
+	return h
+}
+
+func index(v []float64, x float64) int {
+	for i, y := range v {
+		if y == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// happiness returns happiness of students sorted in ascending order.
+func happiness(students []*Student) []float64 {
+	res := make([]float64, len(students))
+	for i, st := range students {
+		res[i] = st.GetHappiness()
+	}
+	sort.Float64s(res)
+	return res
+}
+
+func sum(v []float64) (res float64) {
+	for _, x := range v {
+		res += x
+	}
+	return
+}
+
+// FairnessReport describes the distribution of happiness of regular students.
+type FairnessReport struct {
+	Min    float64
+	P10    float64
+	P25    float64
+	Median float64
+	P75    float64
+	P90    float64
+	Max    float64
+	Mean   float64
+	Gini   float64
+}
+
+// NewFairnessReport creates a report based on happiness of regular students.
+func NewFairnessReport(students []*Student) *FairnessReport {
+	var sts []*Student
+	for _, st := range students {
+		if !st.Priority {
+			sts = append(sts, st)
+		}
+	}
+	h := happiness(sts)
+	if len(h) == 0 {
+		return &FairnessReport{}
+	}
+	return &FairnessReport{
+		Min:    h[0],
+		P10:    percentile(h, 10),
+		P25:    percentile(h, 25),
+		Median: percentile(h, 50),
+		P75:    percentile(h, 75),
+		P90:    percentile(h, 90),
+		Max:    h[len(h)-1],
+		Mean:   sum(h) / float64(len(h)),
+		Gini:   gini(h),
+	}
+}
+
+// percentile returns p-th percentile of sorted values using the nearest-rank method.
+func percentile(v []float64, p int) float64 {
+	i := int(math.Ceil(float64(p)/100.0*float64(len(v)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return v[i]
+}
+
+// gini returns the Gini coefficient of sorted values.
+// 0 means that everybody is equally happy.
+func gini(v []float64) float64 {
+	total := sum(v)
+	if total == 0 {
+		return 0
+	}
+	var w float64
+	for i, x := range v {
+		w += float64(i+1) * x
+	}
+	n := float64(len(v))
+	return 2*w/(n*total) - (n+1)/n
+}
+
+// Save creates a slice with statistics of a report, each row contains a name and a value.
+func (r *FairnessReport) Save() [][]string {
+	return [][]string{
+		{"min", fmt.Sprintf("%.2f", r.Min)},
+		{"p10", fmt.Sprintf("%.2f", r.P10)},
+		{"p25", fmt.Sprintf("%.2f", r.P25)},
+		{"median", fmt.Sprintf("%.2f", r.Median)},
+		{"p75", fmt.Sprintf("%.2f", r.P75)},
+		{"p90", fmt.Sprintf("%.2f", r.P90)},
+		{"max", fmt.Sprintf("%.2f", r.Max)},
+		{"mean", fmt.Sprintf("%.2f", r.Mean)},
+		{"gini", fmt.Sprintf("%.4f", r.Gini)},
+	}
+}
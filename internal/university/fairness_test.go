@@ -0,0 +1,243 @@
+package university
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestNewFairnessPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		n    string
+		want FairnessPolicy
+		err  error
+	}{
+		{
+			name: "Returns maxmin policy",
+			n:    "maxmin",
+			want: FairnessMaxMin,
+		},
+		{
+			name: "Returns leximin policy",
+			n:    "leximin",
+			want: FairnessLeximin,
+		},
+		{
+			name: "Fails on incorrect name",
+			n:    "wrong",
+			err:  ErrWrongFairnessPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFairnessPolicy(tt.n)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("NewFairnessPolicy() error = %v, err %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("NewFairnessPolicy() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFairnessPolicy_improves(t *testing.T) {
+	type args struct {
+		h    []float64
+		prev []float64
+		next []float64
+	}
+	tests := []struct {
+		name string
+		p    FairnessPolicy
+		args args
+		want bool
+	}{
+		{
+			name: "Maxmin prefers higher minimum",
+			p:    FairnessMaxMin,
+			args: args{h: []float64{25, 100}, prev: []float64{25, 100}, next: []float64{50, 50}},
+			want: true,
+		},
+		{
+			name: "Maxmin prefers higher total for the same minimum",
+			p:    FairnessMaxMin,
+			args: args{h: []float64{25, 50}, prev: []float64{50}, next: []float64{100}},
+			want: true,
+		},
+		{
+			name: "Maxmin takes the minimum from values which are kept",
+			p:    FairnessMaxMin,
+			args: args{h: []float64{10, 40, 60}, prev: []float64{10}, next: []float64{80}},
+			want: true,
+		},
+		{
+			name: "Maxmin rejects lower minimum",
+			p:    FairnessMaxMin,
+			args: args{h: []float64{30, 40}, prev: []float64{40}, next: []float64{20}},
+		},
+		{
+			name: "Leximin compares the next values for the same minimum",
+			p:    FairnessLeximin,
+			args: args{h: []float64{25, 50, 100}, prev: []float64{50, 100}, next: []float64{75, 75}},
+			want: true,
+		},
+		{
+			name: "Leximin rejects lower smallest changed value",
+			p:    FairnessLeximin,
+			args: args{h: []float64{25, 50}, prev: []float64{25, 50}, next: []float64{90, 10}},
+		},
+		{
+			name: "Leximin rejects unchanged values",
+			p:    FairnessLeximin,
+			args: args{h: []float64{25, 50}, prev: []float64{25, 50}, next: []float64{50, 25}},
+		},
+		{
+			name: "None never prefers other results",
+			p:    FairnessNone,
+			args: args{h: []float64{0}, prev: []float64{0}, next: []float64{100}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.improves(tt.args.h, tt.args.prev, tt.args.next); got != tt.want {
+				t.Errorf("FairnessPolicy.improves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_replace(t *testing.T) {
+	got := replace([]float64{10, 20, 30, 40}, []float64{20, 40}, []float64{35, 5})
+	want := []float64{5, 10, 30, 35}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("replace() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewFairnessReport(t *testing.T) {
+	var students []*Student
+	for i, h := range []float64{0, 25, 50, 75, 100} {
+		students = append(students, &Student{
+			Name:      string(rune('a' + i)),
+			Happiness: map[string]float64{"Math": h},
+		})
+	}
+	students = append(students, &Student{
+		Name:      "priority",
+		Priority:  true,
+		Happiness: map[string]float64{"Math": 10},
+	})
+	want := &FairnessReport{
+		Min:    0,
+		P10:    0,
+		P25:    25,
+		Median: 50,
+		P75:    75,
+		P90:    100,
+		Max:    100,
+		Mean:   50,
+		Gini:   0.4,
+	}
+	got := NewFairnessReport(students)
+	if !cmp.Equal(got, want, cmp.Comparer(func(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 })) {
+		t.Errorf("NewFairnessReport() = %v, want %v", got, want)
+	}
+}
+
+func TestSchedule_improveFairness(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		policy   FairnessPolicy
+		students map[string]map[string]int
+		groups   map[string]string
+		want     map[string]string
+		// happy are students whose happiness is 100 after moves
+		happy []string
+	}{
+		{
+			name:     "Swaps students when the least happy one improves",
+			policy:   FairnessMaxMin,
+			students: map[string]map[string]int{"a": {"1": 1, "2": 1}, "b": {"1": 1, "2": 2}},
+			// Student a is happy in both groups, but b is not happy in group 2
+			groups: map[string]string{"a": "1", "b": "2"},
+			want:   map[string]string{"a": "2", "b": "1"},
+			happy:  []string{"a", "b"},
+		},
+		{
+			name:     "Does not place students who ranked no group with maxmin",
+			policy:   FairnessMaxMin,
+			students: map[string]map[string]int{"a": {"1": 1}, "c": nil},
+			groups:   map[string]string{"a": "1"},
+			want:     map[string]string{"a": "1"},
+			happy:    []string{"a"},
+		},
+		{
+			name:     "Does not place students who ranked no group with leximin",
+			policy:   FairnessLeximin,
+			students: map[string]map[string]int{"a": {"1": 1}, "c": nil},
+			groups:   map[string]string{"a": "1"},
+			want:     map[string]string{"a": "1"},
+			happy:    []string{"a"},
+		},
+		{
+			name:     "Does not move students to groups which they did not rank",
+			policy:   FairnessLeximin,
+			students: map[string]map[string]int{"a": {"1": 1}, "b": {"1": 1}},
+			groups:   map[string]string{"a": "1"},
+			want:     map[string]string{"a": "1"},
+			happy:    []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &Subject{
+				Name: "Math",
+				Groups: []*Group{
+					{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+					{Name: "2", Capacity: 1, Weekday: time.Tuesday, StartTime: ten},
+				},
+			}
+			s := &Schedule{
+				Subjects: []*Subject{sub},
+				Fairness: tt.policy,
+			}
+			var students []*Student
+			for _, n := range []string{"a", "b", "c"} {
+				prefs, ok := tt.students[n]
+				if !ok {
+					continue
+				}
+				st := newTestStudent(n, prefs)
+				st.Subjects = []string{"Math"}
+				if g := sub.GetGroup(tt.groups[n]); g != nil {
+					g.Students = append(g.Students, st)
+					st.FinalGroups["Math"] = g
+				}
+				st.CalculateHappiness(s.satisfaction(), sub)
+				students = append(students, st)
+			}
+			s.improveFairness(students)
+			got := make(map[string]string)
+			var happy []string
+			for _, st := range students {
+				if g := st.FinalGroups["Math"]; g != nil {
+					got[st.Name] = g.Name
+				}
+				if st.GetHappiness() == 100.0 {
+					happy = append(happy, st.Name)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Schedule.improveFairness() mismatch (-want +got):\n%s", diff)
+			}
+			if !cmp.Equal(happy, tt.happy) {
+				t.Errorf("Schedule.improveFairness() happy students = %v, want %v", happy, tt.happy)
+			}
+		})
+	}
+}
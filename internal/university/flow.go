@@ -0,0 +1,167 @@
+package university
+
+import "math"
+
+// FlowSolver assigns students to groups by finding a minimum cost flow for each subject.
+// Students are sent to groups through edges which cost is equal to the priority of a group,
+// so the total priority within a subject is minimized and capacities of groups are never exceeded.
+// Priority students are assigned to their preferred groups before the flow is calculated.
+// Students who already have a group of a subject keep it.
+type FlowSolver struct{}
+
+// Solve implements Solver.
+func (f *FlowSolver) Solve(s *Schedule, students []*Student) {
+	s.assignLectures(students)
+	for _, sub := range s.Subjects {
+		gns := sub.GetGroupsNames()
+		if len(gns) == 0 {
+			continue
+		}
+		for _, st := range students {
+			if !st.Priority || !st.Enrolled(sub.Name) || st.FinalGroups[sub.Name] != nil {
+				continue
+			}
+			g := sub.GetGroup(st.GetPreferredGroup(sub.Name, gns))
+			if g == nil {
+				continue
+			}
+			g.PriorityStudents = append(g.PriorityStudents, st)
+			st.FinalGroups[sub.Name] = g
+		}
+	}
+	for _, sub := range s.Subjects {
+		if len(sub.Groups) == 0 {
+			continue
+		}
+		f.solveSubject(sub, students)
+	}
+}
+
+// solveSubject assigns regular students enrolled in a subject to its groups.
+// Students who cannot be sent to any group are left without a final group.
+func (f *FlowSolver) solveSubject(sub *Subject, students []*Student) {
+	var sts []*Student
+	for _, st := range students {
+		if !st.Priority && st.Enrolled(sub.Name) && st.ranks(sub) && st.FinalGroups[sub.Name] == nil {
+			sts = append(sts, st)
+		}
+	}
+	// Nodes: source, students, groups, sink
+	src, sink := 0, len(sts)+len(sub.Groups)+1
+	n := newFlowNetwork(sink + 1)
+	edges := make([][]int, len(sts))
+	for i, st := range sts {
+		n.addEdge(src, i+1, 1, 0)
+		edges[i] = make([]int, len(sub.Groups))
+		for j, g := range sub.Groups {
+			edges[i][j] = -1
+			if !st.CanMove(sub.Name, g) {
+				continue
+			}
+			edges[i][j] = n.addEdge(i+1, len(sts)+j+1, 1, rank(st, sub, g))
+		}
+	}
+	for j, g := range sub.Groups {
+		c := g.Capacity - len(g.PriorityStudents) - len(g.Students)
+		if c < 0 {
+			c = 0
+		}
+		n.addEdge(len(sts)+j+1, sink, c, 0)
+	}
+	n.minCostFlow(src, sink)
+
+	for i, st := range sts {
+		for j, g := range sub.Groups {
+			if edges[i][j] < 0 || n.edges[edges[i][j]].flow == 0 {
+				continue
+			}
+			g.Students = append(g.Students, st)
+			st.FinalGroups[sub.Name] = g
+		}
+	}
+}
+
+// rank returns the priority which a student set to a group.
+// Groups without a priority are ranked after all groups of a subject.
+func rank(st *Student, sub *Subject, g *Group) int {
+	if p := st.Preferences[SubjectGroup{sub.Name, g.Name}]; p > 0 {
+		return p
+	}
+	return len(sub.Groups) + 1
+}
+
+// flowNetwork is a directed graph used to find a minimum cost maximum flow.
+// Each edge is stored together with its residual edge, the residual edge of edge i is i^1.
+type flowNetwork struct {
+	edges []flowEdge
+	adj   [][]int
+}
+
+type flowEdge struct {
+	to   int
+	cap  int
+	cost int
+	flow int
+}
+
+func newFlowNetwork(n int) *flowNetwork {
+	return &flowNetwork{
+		adj: make([][]int, n),
+	}
+}
+
+// addEdge adds an edge to the network and returns its index.
+func (f *flowNetwork) addEdge(from, to, cap, cost int) int {
+	i := len(f.edges)
+	f.edges = append(f.edges, flowEdge{to: to, cap: cap, cost: cost}, flowEdge{to: from, cost: -cost})
+	f.adj[from] = append(f.adj[from], i)
+	f.adj[to] = append(f.adj[to], i+1)
+	return i
+}
+
+// minCostFlow sends the maximum flow from s to t with the minimum total cost.
+// It uses successive shortest paths found with the Bellman-Ford algorithm.
+func (f *flowNetwork) minCostFlow(s, t int) (flow, cost int) {
+	for {
+		dist := make([]int, len(f.adj))
+		prev := make([]int, len(f.adj))
+		for i := range dist {
+			dist[i] = math.MaxInt64
+			prev[i] = -1
+		}
+		dist[s] = 0
+		for updated := true; updated; {
+			updated = false
+			for u := range f.adj {
+				if dist[u] == math.MaxInt64 {
+					continue
+				}
+				for _, i := range f.adj[u] {
+					e := f.edges[i]
+					if e.cap-e.flow > 0 && dist[u]+e.cost < dist[e.to] {
+						dist[e.to] = dist[u] + e.cost
+						prev[e.to] = i
+						updated = true
+					}
+				}
+			}
+		}
+		if dist[t] == math.MaxInt64 {
+			return
+		}
+		// Find bottleneck of the path
+		push := math.MaxInt64
+		for v := t; v != s; v = f.edges[prev[v]^1].to {
+			e := f.edges[prev[v]]
+			if e.cap-e.flow < push {
+				push = e.cap - e.flow
+			}
+		}
+		for v := t; v != s; v = f.edges[prev[v]^1].to {
+			f.edges[prev[v]].flow += push
+			f.edges[prev[v]^1].flow -= push
+		}
+		flow += push
+		cost += push * dist[t]
+	}
+}
@@ -0,0 +1,91 @@
+package university
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_flowNetwork_minCostFlow(t *testing.T) {
+	// Two units can be sent from 0 to 3, the cheapest way costs 1 + 3.
+	n := newFlowNetwork(4)
+	n.addEdge(0, 1, 1, 1)
+	n.addEdge(0, 2, 2, 2)
+	n.addEdge(1, 3, 2, 0)
+	n.addEdge(2, 3, 1, 1)
+	flow, cost := n.minCostFlow(0, 3)
+	if flow != 2 || cost != 4 {
+		t.Errorf("flowNetwork.minCostFlow() = %v, %v, want 2, 4", flow, cost)
+	}
+}
+
+func TestFlowSolver_Solve(t *testing.T) {
+	tests := []struct {
+		name     string
+		groups   []*Group
+		students []*Student
+		priority []string
+		want     map[string]string
+	}{
+		{
+			name: "Minimizes the sum of priorities",
+			groups: []*Group{
+				{Name: "1", Capacity: 1, Weekday: time.Monday},
+				{Name: "2", Capacity: 1, Weekday: time.Tuesday},
+				{Name: "3", Capacity: 1, Weekday: time.Wednesday},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 1, "3": 2}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"1": 2, "2": 1, "3": 3}),
+			},
+			want: map[string]string{
+				"a": "3",
+				"b": "1",
+				"c": "2",
+			},
+		},
+		{
+			name: "Does not exceed capacity",
+			groups: []*Group{
+				{Name: "1", Capacity: 1, Weekday: time.Monday},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				newTestStudent("b", map[string]int{"1": 1}),
+			},
+			priority: []string{"b"},
+			want: map[string]string{
+				"b": "1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Schedule{
+				Subjects: []*Subject{
+					{
+						Name:   "Math",
+						Groups: tt.groups,
+					},
+				},
+			}
+			for _, st := range tt.students {
+				for _, p := range tt.priority {
+					if st.Name == p {
+						st.Priority = true
+					}
+				}
+			}
+			(&FlowSolver{}).Solve(s, tt.students)
+			for _, st := range tt.students {
+				var got string
+				if g := st.FinalGroups["Math"]; g != nil {
+					got = g.Name
+				}
+				if got != tt.want[st.Name] {
+					t.Errorf("FlowSolver.Solve() student %s got = %v, want %v", st.Name, got, tt.want[st.Name])
+				}
+			}
+		})
+	}
+}
@@ -11,11 +11,19 @@ var (
 	ErrWrongClassType = errors.New("incorrect class type, available types: Class, Lecture, Laboratory")
 	// ErrWrongWeekday is returned when a passed weekday is incorrect.
 	ErrWrongWeekday = errors.New("incorrect weekday, available weekdays: Monday, Tuesday, Wednesday, Thursday, Friday")
+	// ErrWrongTimeRange is returned when a passed end time is not after start time.
+	ErrWrongTimeRange = errors.New("incorrect end time: end time has to be after start time")
+	// ErrWrongFrequency is returned when a passed frequency is lower than 1.
+	ErrWrongFrequency = errors.New("incorrect frequency: frequency has to be at least 1")
+	// ErrWrongCapacity is returned when a passed capacity is lower than 1.
+	ErrWrongCapacity = errors.New("incorrect capacity: capacity has to be at least 1")
 )
 
 // GroupError represents an error struct returned when creating new Group.
+// Column - index of an incorrect parameter, see NewGroup for description of parameters.
 type GroupError struct {
-	Err error
+	Column int
+	Err    error
 }
 
 func (e *GroupError) Error() string {
@@ -40,6 +48,10 @@ var types = map[string]ClassType{
 	"Laboratory": Laboratory,
 }
 
+// defaultSemesterWeeks is the number of weeks for which meetings of a group are expanded
+// when its end date is not set.
+const defaultSemesterWeeks = 15
+
 var weekdays = map[string]time.Weekday{
 	"Monday":    time.Monday,
 	"Tuesday":   time.Tuesday,
@@ -49,6 +61,8 @@ var weekdays = map[string]time.Weekday{
 }
 
 // Group represents a single students group for one subject.
+// EndDate - the last day on which a group can meet, usually the end of a semester.
+// Waitlist - students who ranked a group higher than their final group in order of promotion, see Schedule.Withdraw.
 // It implements sort.Interface based on students' happiness in a slice containing students.
 type Group struct {
 	Type             ClassType
@@ -58,12 +72,14 @@ type Group struct {
 	EndTime          time.Time
 	Place            string
 	StartDate        time.Time
+	EndDate          time.Time
 	Frequency        int
 	Name             string
 	Capacity         int
 	Students         []*Student
 	PriorityStudents []*Student
 	SubGroups        []*Group
+	Waitlist         []*Student
 }
 
 func (g *Group) Len() int {
@@ -95,35 +111,44 @@ func (g *Group) Swap(i, j int) {
 func NewGroup(subjects []string) (*Group, error) {
 	t := types[subjects[1]]
 	if t == "" {
-		return nil, &GroupError{Err: ErrWrongClassType}
+		return nil, &GroupError{Column: 1, Err: ErrWrongClassType}
 	}
 
 	w := weekdays[subjects[3]]
 	if w == 0 {
-		return nil, &GroupError{Err: ErrWrongWeekday}
+		return nil, &GroupError{Column: 3, Err: ErrWrongWeekday}
 	}
 
 	st, err := time.Parse(timeLayout, subjects[4])
 	if err != nil {
-		return nil, &GroupError{Err: err}
+		return nil, &GroupError{Column: 4, Err: err}
 	}
 	et, err := time.Parse(timeLayout, subjects[5])
 	if err != nil {
-		return nil, &GroupError{Err: err}
+		return nil, &GroupError{Column: 5, Err: err}
+	}
+	if !et.After(st) {
+		return nil, &GroupError{Column: 5, Err: ErrWrongTimeRange}
 	}
 
 	d, err := time.Parse(dateLayout, subjects[7])
 	if err != nil {
-		return nil, &GroupError{Err: err}
+		return nil, &GroupError{Column: 7, Err: err}
 	}
 
 	f, err := strconv.Atoi(subjects[8])
 	if err != nil {
-		return nil, &GroupError{Err: err}
+		return nil, &GroupError{Column: 8, Err: err}
+	}
+	if f < 1 {
+		return nil, &GroupError{Column: 8, Err: ErrWrongFrequency}
 	}
 	c, err := strconv.Atoi(subjects[10])
 	if err != nil {
-		return nil, &GroupError{Err: err}
+		return nil, &GroupError{Column: 10, Err: err}
+	}
+	if c < 1 {
+		return nil, &GroupError{Column: 10, Err: ErrWrongCapacity}
 	}
 
 	return &Group{
@@ -146,19 +171,93 @@ func (g *Group) Conflicts() int {
 	return (len(g.PriorityStudents) + len(g.Students)) - g.Capacity
 }
 
-// Collide checks if groups are not in the same time.
+// Meetings returns all meetings of a group: the group itself and its subgroups.
+// A group which meets multiple times a week has one subgroup for every additional meeting.
+func (g *Group) Meetings() []*Group {
+	return append([]*Group{g}, g.SubGroups...)
+}
+
+// Collide checks if groups are held in the same time.
+// Groups collide when any of their meetings are held on at least one common date and their hours overlap.
 func (g *Group) Collide(a *Group) bool {
-	// TODO: groups can be twice a week, so they can be on the same weekday and at the same hour
-	// Frequency & start date
+	for _, gm := range g.Meetings() {
+		for _, am := range a.Meetings() {
+			if gm.collide(am) {
+				return true
+			}
+		}
+	}
+	return false
+}
 
+// collide checks if single meetings of groups are held in the same time.
+func (g *Group) collide(a *Group) bool {
 	if g.Weekday != a.Weekday {
 		return false
 	}
-	// There is a fixed time schedule at the university
-	if g.StartTime != a.StartTime {
+	if !g.overlaps(a) {
 		return false
 	}
-	return true
+	// Groups without a start date are assumed to meet every week
+	if g.StartDate.IsZero() || a.StartDate.IsZero() {
+		return true
+	}
+	dates := make(map[string]bool)
+	for _, d := range g.Dates() {
+		dates[d.Format(dateLayout)] = true
+	}
+	for _, d := range a.Dates() {
+		if dates[d.Format(dateLayout)] {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps checks if hours of groups overlap within a day.
+func (g *Group) overlaps(a *Group) bool {
+	gs, as := minutes(g.StartTime), minutes(a.StartTime)
+	if gs == as {
+		return true
+	}
+	return gs < a.end() && as < g.end()
+}
+
+// end returns minute of a day on which a group ends.
+// Groups without a valid end time end when they start.
+func (g *Group) end() int {
+	s, e := minutes(g.StartTime), minutes(g.EndTime)
+	if e < s {
+		return s
+	}
+	return e
+}
+
+func minutes(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// Dates returns all dates on which a group meets.
+// Meetings start on the first Weekday after StartDate and are repeated every Frequency weeks until EndDate.
+// If EndDate is not set, meetings are repeated for defaultSemesterWeeks weeks.
+func (g *Group) Dates() []time.Time {
+	f := g.Frequency
+	if f < 1 {
+		f = 1
+	}
+	end := g.EndDate
+	if end.IsZero() {
+		end = g.StartDate.AddDate(0, 0, 7*defaultSemesterWeeks-1)
+	}
+	d := g.StartDate
+	for d.Weekday() != g.Weekday {
+		d = d.AddDate(0, 0, 1)
+	}
+	var res []time.Time
+	for ; !d.After(end); d = d.AddDate(0, 0, 7*f) {
+		res = append(res, d)
+	}
+	return res
 }
 
 // RemoveStudent removes student from group.
@@ -181,6 +280,18 @@ func (g *Group) Save() [][]string {
 	return res
 }
 
+// Roster creates a slice with students who will attend this group of subject sub.
+// Each row contains subject name, group name, class type, student name and whether a student has priority.
+func (g *Group) Roster(sub string) [][]string {
+	var res [][]string
+	for _, sts := range [][]*Student{g.PriorityStudents, g.Students} {
+		for _, st := range sts {
+			res = append(res, []string{sub, g.Name, string(g.Type), st.Name, strconv.FormatBool(st.Priority)})
+		}
+	}
+	return res
+}
+
 func saveStudents(sts []*Student, res [][]string, i *int) {
 	for _, st := range sts {
 		r := make([]string, 1)
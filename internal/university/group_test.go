@@ -105,6 +105,62 @@ func TestNewGroup(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "End time before start time",
+			args: args{
+				[]string{
+					"Programming",
+					"Laboratory",
+					"teacher",
+					"Thursday",
+					"14:00",
+					"13:30",
+				},
+			},
+			err: &GroupError{
+				Err: ErrWrongTimeRange,
+			},
+		},
+		{
+			name: "Frequency lower than 1",
+			args: args{
+				[]string{
+					"Programming",
+					"Laboratory",
+					"teacher",
+					"Thursday",
+					"14:00",
+					"15:30",
+					"C-2 313",
+					"03-05-20",
+					"0",
+				},
+			},
+			err: &GroupError{
+				Err: ErrWrongFrequency,
+			},
+		},
+		{
+			name: "Capacity lower than 1",
+			args: args{
+				[]string{
+					"Programming",
+					"Laboratory",
+					"teacher",
+					"Thursday",
+					"14:00",
+					"15:30",
+					"C-2 313",
+					"03-05-20",
+					"2",
+					"1b",
+					"0",
+				},
+			},
+			err: &GroupError{
+				Err: ErrWrongCapacity,
+			},
+		},
 		{
 			name: "Incorrect frequency",
 			args: args{
@@ -121,7 +177,7 @@ func TestNewGroup(t *testing.T) {
 				},
 			},
 			err: &GroupError{
-				&strconv.NumError{
+				Err: &strconv.NumError{
 					Func: "Atoi",
 					Num:  "wrong",
 					Err:  strconv.ErrSyntax,
@@ -286,6 +342,119 @@ func TestGroup_Collide(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "Returns true because hours of groups overlap",
+			args: args{
+				a: &Group{
+					Weekday:   time.Monday,
+					StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+				},
+			},
+			g: &Group{
+				Weekday:   time.Monday,
+				StartTime: time.Date(0, 1, 1, 13, 15, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 14, 45, 0, 0, time.UTC),
+			},
+			want: true,
+		},
+		{
+			name: "Returns false because one group ends when the other starts",
+			args: args{
+				a: &Group{
+					Weekday:   time.Monday,
+					StartTime: time.Date(0, 1, 1, 14, 45, 0, 0, time.UTC),
+					EndTime:   time.Date(0, 1, 1, 16, 15, 0, 0, time.UTC),
+				},
+			},
+			g: &Group{
+				Weekday:   time.Monday,
+				StartTime: time.Date(0, 1, 1, 13, 15, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 14, 45, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Returns false because groups meet in different weeks",
+			args: args{
+				a: &Group{
+					Weekday:   time.Tuesday,
+					StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+					StartDate: time.Date(2020, 2, 25, 0, 0, 0, 0, time.UTC),
+					Frequency: 2,
+				},
+			},
+			g: &Group{
+				Weekday:   time.Tuesday,
+				StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+				StartDate: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC),
+				Frequency: 2,
+			},
+		},
+		{
+			name: "Returns true because groups meet in common weeks",
+			args: args{
+				a: &Group{
+					Weekday:   time.Tuesday,
+					StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+					StartDate: time.Date(2020, 2, 25, 0, 0, 0, 0, time.UTC),
+					Frequency: 1,
+				},
+			},
+			g: &Group{
+				Weekday:   time.Tuesday,
+				StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+				StartDate: time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC),
+				Frequency: 2,
+			},
+			want: true,
+		},
+		{
+			name: "Returns true because the second meeting of a group collides",
+			args: args{
+				a: &Group{
+					Weekday:   time.Thursday,
+					StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+				},
+			},
+			g: &Group{
+				Weekday:   time.Monday,
+				StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+				SubGroups: []*Group{
+					{
+						Weekday:   time.Thursday,
+						StartTime: time.Date(0, 1, 1, 15, 0, 0, 0, time.UTC),
+						EndTime:   time.Date(0, 1, 1, 16, 30, 0, 0, time.UTC),
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Returns false because one group ends before the other starts",
+			args: args{
+				a: &Group{
+					Weekday:   time.Tuesday,
+					StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+					EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+					StartDate: time.Date(2020, 2, 25, 0, 0, 0, 0, time.UTC),
+					EndDate:   time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC),
+					Frequency: 1,
+				},
+			},
+			g: &Group{
+				Weekday:   time.Tuesday,
+				StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+				StartDate: time.Date(2020, 4, 7, 0, 0, 0, 0, time.UTC),
+				Frequency: 1,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -297,6 +466,62 @@ func TestGroup_Collide(t *testing.T) {
 	}
 }
 
+func TestGroup_Dates(t *testing.T) {
+	tests := []struct {
+		name string
+		g    *Group
+		want []time.Time
+	}{
+		{
+			name: "Returns dates of a biweekly group",
+			g: &Group{
+				Weekday:   time.Thursday,
+				StartDate: time.Date(2020, 3, 5, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2020, 4, 16, 0, 0, 0, 0, time.UTC),
+				Frequency: 2,
+			},
+			want: []time.Time{
+				time.Date(2020, 3, 5, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 3, 19, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 4, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 4, 16, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Starts from the first weekday after start date",
+			g: &Group{
+				Weekday:   time.Friday,
+				StartDate: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2020, 3, 13, 0, 0, 0, 0, time.UTC),
+				Frequency: 1,
+			},
+			want: []time.Time{
+				time.Date(2020, 3, 6, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 3, 13, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Uses default semester length when end date is not set",
+			g: &Group{
+				Weekday:   time.Monday,
+				StartDate: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+				Frequency: 8,
+			},
+			want: []time.Time{
+				time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2020, 4, 27, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.g.Dates(); !cmp.Equal(got, tt.want) {
+				t.Errorf("Group.Dates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGroup_RemoveStudent(t *testing.T) {
 	type args struct {
 		st *Student
@@ -452,3 +677,19 @@ func TestGroup_Save(t *testing.T) {
 		})
 	}
 }
+
+func TestGroup_Roster(t *testing.T) {
+	g := &Group{
+		Name:             "1",
+		Type:             Laboratory,
+		Students:         []*Student{{Name: "a"}},
+		PriorityStudents: []*Student{{Name: "b", Priority: true}},
+	}
+	want := [][]string{
+		{"Math", "1", "Laboratory", "b", "true"},
+		{"Math", "1", "Laboratory", "a", "false"},
+	}
+	if got := g.Roster("Math"); !cmp.Equal(got, want) {
+		t.Errorf("Group.Roster() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,391 @@
+package university
+
+import "sort"
+
+// ChangeReason describes why a group or lecture section of a student changed compared to a baseline.
+type ChangeReason string
+
+const (
+	// ReasonNewStudent - a student did not exist in a baseline.
+	ReasonNewStudent ChangeReason = "new student"
+	// ReasonStudentRemoved - a student does not exist anymore.
+	ReasonStudentRemoved ChangeReason = "student removed"
+	// ReasonPreferencesChanged - a student changed preferences of a subject, chosen subjects or priority.
+	ReasonPreferencesChanged ChangeReason = "preferences changed"
+	// ReasonGroupRemoved - a group or a subject of a student does not exist anymore.
+	ReasonGroupRemoved ChangeReason = "group removed"
+	// ReasonTimeChanged - a group of a student is held at a different time.
+	ReasonTimeChanged ChangeReason = "time of a group changed"
+	// ReasonCapacityReduced - capacity of a group was reduced below the number of its students.
+	ReasonCapacityReduced ChangeReason = "capacity of a group reduced"
+	// ReasonPlaceFreed - a group which a student ranked higher than their group was full and has free places.
+	ReasonPlaceFreed ChangeReason = "place freed in a preferred group"
+	// ReasonReassigned - a solver placed a student in a different group, e.g. because it gives them more happiness.
+	ReasonReassigned ChangeReason = "reassigned"
+)
+
+// Change represents a student whose group or lecture section of a subject differs from a baseline.
+// From and To are empty when a student had or has no group.
+type Change struct {
+	Student string
+	Subject string
+	Type    ClassType
+	From    string
+	To      string
+	Reason  ChangeReason
+}
+
+// Save creates a row with student name, subject name, class type, previous group, new group and reason.
+func (c *Change) Save() []string {
+	return []string{c.Student, c.Subject, string(c.Type), c.From, c.To, string(c.Reason)}
+}
+
+// Baseline represents a previous enrollment, e.g. restored from a stored run.
+// Students are linked to groups of Schedule by their final groups and lecture sections.
+type Baseline struct {
+	Schedule *Schedule
+	Students []*Student
+}
+
+// affected contains reasons for which students have to be enrolled again by student name and subject name.
+type affected map[string]map[string]ChangeReason
+
+// mark marks a subject of a student as affected, the first reason is kept.
+func (a affected) mark(st, sub string, r ChangeReason) {
+	if a[st] == nil {
+		a[st] = make(map[string]ChangeReason)
+	}
+	if _, ok := a[st][sub]; !ok {
+		a[st][sub] = r
+	}
+}
+
+// Reenroll enrolls students keeping groups and lecture sections which they received in baseline b.
+// Groups are matched by subject and group name, students by name.
+// Only subjects affected by changes since the baseline are enrolled again: subjects of new students,
+// subjects whose preferences changed, subjects whose group of a student was removed, is held at a different time
+// or does not fit its reduced capacity; only the group or lecture section which does not fit is left.
+// Affected students are placed with FlowSolver, which never moves students who are already in groups,
+// and the Fairness policy of a schedule is not applied, so other students keep their groups.
+// Afterwards free places of groups which were full in the baseline are offered to waitlisted students in order,
+// see Schedule.Withdraw, so one free place moves at most one student into the group.
+// Changes of the result contain every student whose group differs from the baseline.
+// It returns the same errors as Enroll.
+func (s *Schedule) Reenroll(students []*Student, b *Baseline) (*EnrollResult, error) {
+	if err := s.ValidateStudents(students); err != nil {
+		return nil, err
+	}
+	prev := make(map[string]*Student)
+	for _, st := range b.Students {
+		prev[st.Name] = st
+	}
+	a := make(affected)
+	for _, st := range students {
+		p := prev[st.Name]
+		for _, sub := range s.Subjects {
+			en, pen := st.Enrolled(sub.Name), p != nil && p.Enrolled(sub.Name)
+			switch {
+			case !en && !pen:
+			case p == nil:
+				a.mark(st.Name, sub.Name, ReasonNewStudent)
+			case en != pen || st.Priority != p.Priority || !samePreferences(st, p, sub):
+				a.mark(st.Name, sub.Name, ReasonPreferencesChanged)
+			default:
+				if r := placementChanged(p, sub); r != "" {
+					a.mark(st.Name, sub.Name, r)
+				}
+			}
+		}
+	}
+
+	// Unaffected students keep their groups
+	for _, st := range students {
+		p := prev[st.Name]
+		if p == nil {
+			continue
+		}
+		for _, sub := range s.Subjects {
+			if _, ok := a[st.Name][sub.Name]; ok || !st.Enrolled(sub.Name) {
+				continue
+			}
+			if l := p.FinalLectures[sub.Name]; l != nil {
+				st.place(sub.Name, sub.GetLecture(l.Name))
+			}
+			if g := p.FinalGroups[sub.Name]; g != nil {
+				st.place(sub.Name, sub.GetGroup(g.Name))
+			}
+		}
+	}
+
+	for _, sub := range s.Subjects {
+		for _, g := range append(append([]*Group{}, sub.Lectures...), sub.Groups...) {
+			c := g.Conflicts()
+			if c <= 0 {
+				continue
+			}
+			// Students who ranked a group the lowest leave it first
+			sts := append([]*Student{}, g.Students...)
+			sort.SliceStable(sts, func(i, j int) bool {
+				return typeRank(sts[i], sub, g) > typeRank(sts[j], sub, g)
+			})
+			if c > len(sts) {
+				c = len(sts)
+			}
+			for _, st := range sts[:c] {
+				st.unplace(sub.Name, g)
+				a.mark(st.Name, sub.Name, ReasonCapacityReduced)
+			}
+		}
+	}
+	s.enrollAffected(students, a)
+
+	// Places left free in groups which were full are offered to waitlisted students in order, as after a withdrawal
+	s.buildWaitlists(students)
+	for _, sub := range s.Subjects {
+		for _, g := range append(append([]*Group{}, sub.Lectures...), sub.Groups...) {
+			if g.Conflicts() >= 0 || !wasFull(b.Schedule, sub.Name, g) {
+				continue
+			}
+			s.fill(sub, g, func(st *Student, from, to *Group) {
+				a.mark(st.Name, sub.Name, ReasonPlaceFreed)
+			})
+		}
+	}
+
+	s.resolveLectures(students)
+	s.calculateHappiness(students)
+	s.buildWaitlists(students)
+	printHappiness(students)
+	res, err := s.result(students)
+	if res != nil {
+		res.Changes = s.changes(b, students, a)
+	}
+	return res, err
+}
+
+// enrollAffected enrolls students with FlowSolver only in subjects affected according to a.
+func (s *Schedule) enrollAffected(students []*Student, a affected) {
+	var sts []*Student
+	subs := make(map[*Student][]string)
+	for _, st := range students {
+		if len(a[st.Name]) == 0 {
+			continue
+		}
+		subs[st] = st.Subjects
+		as := []string{}
+		for _, sub := range s.Subjects {
+			if _, ok := a[st.Name][sub.Name]; ok && st.Enrolled(sub.Name) {
+				as = append(as, sub.Name)
+			}
+		}
+		st.Subjects = as
+		sts = append(sts, st)
+	}
+	(&FlowSolver{}).Solve(s, sts)
+	for st, ss := range subs {
+		st.Subjects = ss
+	}
+}
+
+// samePreferences checks if students a and b set the same priorities to groups of subject sub.
+// Groups which do not exist in the subject are not compared.
+func samePreferences(a, b *Student, sub *Subject) bool {
+	for _, x := range [][2]*Student{{a, b}, {b, a}} {
+		for k, v := range x[0].Preferences {
+			if k.Subject != sub.Name || sub.GetGroup(k.Group) == nil && sub.GetLecture(k.Group) == nil {
+				continue
+			}
+			if x[1].Preferences[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// placementChanged returns the reason why a group or lecture section of student p from a baseline
+// cannot be kept in subject sub, it returns an empty reason when both can be kept.
+func placementChanged(p *Student, sub *Subject) ChangeReason {
+	for _, pg := range []*Group{p.FinalLectures[sub.Name], p.FinalGroups[sub.Name]} {
+		if pg == nil {
+			continue
+		}
+		g := sub.GetGroup(pg.Name)
+		if pg.Type == Lecture {
+			g = sub.GetLecture(pg.Name)
+		}
+		if g == nil {
+			return ReasonGroupRemoved
+		}
+		if !sameTime(g, pg) {
+			return ReasonTimeChanged
+		}
+	}
+	return ""
+}
+
+// sameTime checks if all meetings of groups are held at the same time.
+func sameTime(a, b *Group) bool {
+	am, bm := a.Meetings(), b.Meetings()
+	if len(am) != len(bm) {
+		return false
+	}
+	for i := range am {
+		x, y := am[i], bm[i]
+		if x.Weekday != y.Weekday || !x.StartTime.Equal(y.StartTime) || !x.EndTime.Equal(y.EndTime) ||
+			!x.StartDate.Equal(y.StartDate) || !x.EndDate.Equal(y.EndDate) || x.Frequency != y.Frequency {
+			return false
+		}
+	}
+	return true
+}
+
+// wasFull checks if a group of subject sub was full in baseline schedule s, groups which did not exist are treated as full.
+func wasFull(s *Schedule, sub string, g *Group) bool {
+	bs := s.GetSubject(sub)
+	if bs == nil {
+		return true
+	}
+	bg := bs.GetGroup(g.Name)
+	if g.Type == Lecture {
+		bg = bs.GetLecture(g.Name)
+	}
+	return bg == nil || bg.Conflicts() >= 0
+}
+
+// typeRank returns the priority which a student set to a group or a lecture section.
+func typeRank(st *Student, sub *Subject, g *Group) int {
+	if g.Type == Lecture {
+		return lectureRank(st, sub, g)
+	}
+	return rank(st, sub, g)
+}
+
+// place assigns a student to a group or a lecture section of subject sub, nothing is done when the group is nil.
+func (s *Student) place(sub string, g *Group) {
+	if g == nil {
+		return
+	}
+	if s.Priority {
+		g.PriorityStudents = append(g.PriorityStudents, s)
+	} else {
+		g.Students = append(g.Students, s)
+	}
+	if g.Type == Lecture {
+		if s.FinalLectures == nil {
+			s.FinalLectures = make(map[string]*Group)
+		}
+		s.FinalLectures[sub] = g
+		return
+	}
+	s.FinalGroups[sub] = g
+}
+
+// unplace removes a student from group g of subject sub, the placement of the other type is kept.
+func (s *Student) unplace(sub string, g *Group) {
+	g.leave(s)
+	if g.Type == Lecture {
+		delete(s.FinalLectures, sub)
+		return
+	}
+	delete(s.FinalGroups, sub)
+}
+
+// changes returns students whose groups and lecture sections differ from baseline b
+// sorted by student name, subject name and type, lecture sections first.
+// Every change has the reason for which a subject of a student was enrolled again,
+// changes of subjects which were not marked in a are explained by removed groups or reassignment.
+func (s *Schedule) changes(b *Baseline, students []*Student, a affected) []*Change {
+	prev, cur := make(map[string]*Student), make(map[string]*Student)
+	for _, st := range b.Students {
+		prev[st.Name] = st
+	}
+	for _, st := range students {
+		cur[st.Name] = st
+	}
+	var names []string
+	for n := range prev {
+		if cur[n] == nil {
+			names = append(names, n)
+		}
+	}
+	for n := range cur {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	res := []*Change{}
+	for _, n := range names {
+		p, st := prev[n], cur[n]
+		subs := make(map[string]bool)
+		for _, x := range []*Student{p, st} {
+			if x == nil {
+				continue
+			}
+			for _, gs := range []map[string]*Group{x.FinalLectures, x.FinalGroups} {
+				for k, g := range gs {
+					if g != nil {
+						subs[k] = true
+					}
+				}
+			}
+		}
+		var sns []string
+		for k := range subs {
+			sns = append(sns, k)
+		}
+		sort.Strings(sns)
+		for _, sub := range sns {
+			for _, lecture := range []bool{true, false} {
+				from, to := finalGroup(p, sub, lecture), finalGroup(st, sub, lecture)
+				if from == to || from != nil && to != nil && from.Name == to.Name {
+					continue
+				}
+				c := &Change{Student: n, Subject: sub}
+				if from != nil {
+					c.Type, c.From = from.Type, from.Name
+				}
+				if to != nil {
+					c.Type, c.To = to.Type, to.Name
+				}
+				switch r, ok := a[n][sub]; {
+				case st == nil:
+					c.Reason = ReasonStudentRemoved
+				case p == nil:
+					c.Reason = ReasonNewStudent
+				case ok:
+					c.Reason = r
+				case from != nil && s.missing(sub, from):
+					c.Reason = ReasonGroupRemoved
+				default:
+					c.Reason = ReasonReassigned
+				}
+				res = append(res, c)
+			}
+		}
+	}
+	return res
+}
+
+// missing checks if a group or a lecture section of subject sub from a baseline does not exist in a schedule.
+func (s *Schedule) missing(sub string, g *Group) bool {
+	subject := s.GetSubject(sub)
+	if subject == nil {
+		return true
+	}
+	if g.Type == Lecture {
+		return subject.GetLecture(g.Name) == nil
+	}
+	return subject.GetGroup(g.Name) == nil
+}
+
+// finalGroup returns a lecture section or a group of subject sub of a student, it returns nil for a nil student.
+func finalGroup(st *Student, sub string, lecture bool) *Group {
+	if st == nil {
+		return nil
+	}
+	if lecture {
+		return st.FinalLectures[sub]
+	}
+	return st.FinalGroups[sub]
+}
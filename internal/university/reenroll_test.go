@@ -0,0 +1,252 @@
+package university
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchedule_Reenroll(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     [][]string
+		students []*Student
+		groups   map[string]string
+		changes  []*Change
+	}{
+		{
+			name: "Keeps groups when nothing changed",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "2"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			},
+			groups:  map[string]string{"a": "1", "b": "3", "c": "2", "d": "3"},
+			changes: []*Change{},
+		},
+		{
+			name: "Enrolls new students",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "3"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+				newTestStudent("e", map[string]int{"3": 1}),
+			},
+			groups: map[string]string{"a": "1", "b": "3", "c": "2", "d": "3", "e": "3"},
+			changes: []*Change{
+				{Student: "e", Subject: "Math", Type: Class, To: "3", Reason: ReasonNewStudent},
+			},
+		},
+		{
+			name: "Reports removed students",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "2"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+			},
+			groups: map[string]string{"a": "1", "b": "3", "c": "2"},
+			changes: []*Change{
+				{Student: "d", Subject: "Math", Type: Class, From: "3", Reason: ReasonStudentRemoved},
+			},
+		},
+		{
+			name: "Enrolls students whose preferences changed",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "3"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"3": 1, "2": 2}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			},
+			groups: map[string]string{"a": "1", "b": "2", "c": "3", "d": "3"},
+			changes: []*Change{
+				{Student: "b", Subject: "Math", Type: Class, From: "3", To: "2", Reason: ReasonPlaceFreed},
+				{Student: "c", Subject: "Math", Type: Class, From: "2", To: "3", Reason: ReasonPreferencesChanged},
+			},
+		},
+		{
+			name: "Moves students of removed groups",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "4", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "3"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "3": 3}),
+				newTestStudent("c", map[string]int{"3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			},
+			groups: map[string]string{"a": "1", "b": "3", "c": "3", "d": "3"},
+			changes: []*Change{
+				{Student: "c", Subject: "Math", Type: Class, From: "2", To: "3", Reason: ReasonGroupRemoved},
+			},
+		},
+		{
+			name: "Enrolls students again when time of a group changed",
+			rows: [][]string{
+				{"Math", "Class", "T", "Thursday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "2"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			},
+			groups:  map[string]string{"a": "1", "b": "3", "c": "2", "d": "3"},
+			changes: []*Change{},
+		},
+		{
+			name: "Promotes students when a full group has free places",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "2"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "2"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			},
+			groups: map[string]string{"a": "1", "b": "1", "c": "2", "d": "3"},
+			changes: []*Change{
+				{Student: "b", Subject: "Math", Type: Class, From: "3", To: "1", Reason: ReasonPlaceFreed},
+			},
+		},
+		{
+			name: "Moves one waitlisted student into each free place",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "2"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "2"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "2"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			},
+			groups: map[string]string{"a": "1", "b": "1", "c": "2", "d": "3"},
+			changes: []*Change{
+				{Student: "b", Subject: "Math", Type: Class, From: "3", To: "1", Reason: ReasonPlaceFreed},
+			},
+		},
+		{
+			name: "Removes students who ranked a group the lowest when capacity is reduced",
+			rows: [][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "1"},
+			},
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			},
+			groups: map[string]string{"a": "1", "c": "2", "d": "3"},
+			changes: []*Change{
+				{Student: "b", Subject: "Math", Type: Class, From: "3", Reason: ReasonCapacityReduced},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// In the baseline groups 1 and 2 have one student and group 3 is full
+			bs, err := NewSchedule([][]string{
+				{"Math", "Class", "T", "Monday", "10:00", "11:30", "A", "03-02-20", "1", "1", "1"},
+				{"Math", "Class", "T", "Tuesday", "10:00", "11:30", "A", "03-03-20", "1", "2", "1"},
+				{"Math", "Class", "T", "Wednesday", "10:00", "11:30", "A", "03-04-20", "1", "3", "2"},
+			})
+			if err != nil {
+				t.Fatalf("NewSchedule() error = %v", err)
+			}
+			bsts := []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"2": 1, "3": 2, "1": 3}),
+				newTestStudent("d", map[string]int{"3": 1}),
+			}
+			bgs := map[string]string{"a": "1", "b": "3", "c": "2", "d": "3"}
+			for _, st := range bsts {
+				st.place("Math", bs.Subjects[0].GetGroup(bgs[st.Name]))
+			}
+			s, err := NewSchedule(tt.rows)
+			if err != nil {
+				t.Fatalf("NewSchedule() error = %v", err)
+			}
+			res, err := s.Reenroll(tt.students, &Baseline{Schedule: bs, Students: bsts})
+			if err != nil {
+				t.Fatalf("Schedule.Reenroll() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.changes, res.Changes); diff != "" {
+				t.Errorf("Schedule.Reenroll() changes mismatch (-want +got):\n%s", diff)
+			}
+			groups := make(map[string]string)
+			for _, g := range s.Subjects[0].Groups {
+				for _, st := range g.Students {
+					groups[st.Name] = g.Name
+				}
+			}
+			if diff := cmp.Diff(tt.groups, groups); diff != "" {
+				t.Errorf("Schedule.Reenroll() groups mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStudent_unplace(t *testing.T) {
+	l := &Group{Name: "Lecture", Type: Lecture, Capacity: 2}
+	g := &Group{Name: "1", Type: Class, Capacity: 2}
+	st := newTestStudent("a", map[string]int{"1": 1})
+	st.place("Math", l)
+	st.place("Math", g)
+
+	st.unplace("Math", g)
+	if st.FinalGroups["Math"] != nil || len(g.Students) != 0 {
+		t.Errorf("Student.unplace() kept the student in group %s", g.Name)
+	}
+	if st.FinalLectures["Math"] != l || len(l.Students) != 1 {
+		t.Errorf("Student.unplace() removed the student from the lecture")
+	}
+}
+
+func TestEnrollResult_SaveChanges(t *testing.T) {
+	r := &EnrollResult{
+		Changes: []*Change{
+			{Student: "a", Subject: "Math", Type: Class, From: "2", To: "1", Reason: ReasonPlaceFreed},
+			{Student: "b", Subject: "Math", Type: Lecture, From: "Lecture", Reason: ReasonStudentRemoved},
+		},
+	}
+	want := [][]string{
+		{"a", "Math", "Class", "2", "1", "place freed in a preferred group"},
+		{"b", "Math", "Lecture", "Lecture", "", "student removed"},
+	}
+	if diff := cmp.Diff(want, r.SaveChanges()); diff != "" {
+		t.Errorf("EnrollResult.SaveChanges() mismatch (-want +got):\n%s", diff)
+	}
+}
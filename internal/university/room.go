@@ -0,0 +1,172 @@
+package university
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrRoomClash is returned when a room is booked for groups which are held in the same time.
+	ErrRoomClash = errors.New("room is booked for colliding groups")
+	// ErrRoomCapacityExceeded is returned when capacity of a group is higher than capacity of its room.
+	ErrRoomCapacityExceeded = errors.New("capacity of a group exceeds capacity of a room")
+)
+
+// RoomError represents an error struct returned when creating new Room or when a room is used incorrectly.
+type RoomError struct {
+	Name string
+	Err  error
+}
+
+func (e *RoomError) Error() string {
+	return fmt.Sprintf("incorrect room [%s]: %s", e.Name, e.Err.Error())
+}
+
+func (e *RoomError) Unwrap() error {
+	return e.Err
+}
+
+// Room represents a place where groups meet.
+// Capacity - maximum number of students, 0 if it is unknown.
+// Features - equipment of a room, e.g. computers.
+// Meetings - meetings held in a room, they are set by Schedule.Occupancy.
+type Room struct {
+	Name     string
+	Capacity int
+	Features []string
+	Meetings []*Booking
+}
+
+// NewRoom creates a new instance of Room.
+// It returns RoomError when passed parameters are invalid.
+// room:
+// 0 - room name, the same as place of a group
+// 1 - capacity, format: number
+// 2 - features separated by commas, optional
+func NewRoom(room []string) (*Room, error) {
+	c, err := strconv.Atoi(room[1])
+	if err != nil {
+		return nil, &RoomError{Name: room[0], Err: err}
+	}
+	if c < 1 {
+		return nil, &RoomError{Name: room[0], Err: ErrWrongCapacity}
+	}
+	r := &Room{
+		Name:     room[0],
+		Capacity: c,
+	}
+	if len(room) > 2 {
+		for _, f := range strings.Split(room[2], ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				r.Features = append(r.Features, f)
+			}
+		}
+	}
+	return r, nil
+}
+
+// GetRoom returns a Room with a passed name from the rooms of a schedule.
+func (s *Schedule) GetRoom(n string) *Room {
+	for _, r := range s.Rooms {
+		if r.Name == n {
+			return r
+		}
+	}
+	return nil
+}
+
+// Occupancy returns all places of a schedule sorted by name together with meetings held in them.
+// Places which are not among the rooms of a schedule are returned with unknown capacity.
+func (s *Schedule) Occupancy() []*Room {
+	rooms := make(map[string]*Room)
+	var res []*Room
+	for _, r := range s.Rooms {
+		rooms[r.Name] = &Room{Name: r.Name, Capacity: r.Capacity, Features: r.Features}
+		res = append(res, rooms[r.Name])
+	}
+	for _, b := range s.bookings() {
+		n := b.Meeting.Place
+		if n == "" {
+			continue
+		}
+		r := rooms[n]
+		if r == nil {
+			r = &Room{Name: n}
+			rooms[n] = r
+			res = append(res, r)
+		}
+		r.Meetings = append(r.Meetings, b)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// ValidateRooms checks if no place is booked for two meetings which collide
+// and if capacity of every group does not exceed capacity of its rooms.
+// Capacity is checked only for rooms of a schedule, other places have unknown capacity.
+// It returns RoomError with ErrRoomClash or ErrRoomCapacityExceeded for the first incorrect room.
+func (s *Schedule) ValidateRooms() error {
+	for _, r := range s.Occupancy() {
+		if a, b := collide(r.Meetings); a != nil {
+			return &RoomError{
+				Name: r.Name,
+				Err:  fmt.Errorf("%w: %s %s and %s %s on %s", ErrRoomClash, a.Subject, a.Group.Name, b.Subject, b.Group.Name, a.Meeting.Weekday),
+			}
+		}
+		if s.GetRoom(r.Name) == nil {
+			continue
+		}
+		for _, b := range r.Meetings {
+			if b.Group.Capacity > r.Capacity {
+				return &RoomError{
+					Name: r.Name,
+					Err:  fmt.Errorf("%w: %s %s has %d places, room has %d", ErrRoomCapacityExceeded, b.Subject, b.Group.Name, b.Group.Capacity, r.Capacity),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Hours returns the number of hours during which a room is occupied in an average week, see hours.
+func (r *Room) Hours() float64 {
+	return hours(r.Meetings)
+}
+
+// Save creates a slice with meetings held in a room.
+// Each row contains room name, subject name, group name, class type, weekday, start time, end time, teacher, frequency
+// and the number of students enrolled in a group.
+func (r *Room) Save() [][]string {
+	res := make([][]string, len(r.Meetings))
+	for i, b := range r.Meetings {
+		m := b.Meeting
+		res[i] = []string{
+			r.Name,
+			b.Subject,
+			b.Group.Name,
+			string(b.Group.Type),
+			m.Weekday.String(),
+			m.StartTime.Format(timeLayout),
+			m.EndTime.Format(timeLayout),
+			m.Teacher,
+			strconv.Itoa(m.Frequency),
+			strconv.Itoa(len(b.Group.Students) + len(b.Group.PriorityStudents)),
+		}
+	}
+	return res
+}
+
+// Summary creates a row with room name, capacity, features separated by commas and weekly hours of occupancy.
+// Capacity is empty when it is unknown.
+func (r *Room) Summary() []string {
+	var c string
+	if r.Capacity > 0 {
+		c = strconv.Itoa(r.Capacity)
+	}
+	return []string{r.Name, c, strings.Join(r.Features, ", "), fmt.Sprintf("%.2f", r.Hours())}
+}
@@ -0,0 +1,175 @@
+package university
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestNewRoom(t *testing.T) {
+	tests := []struct {
+		name string
+		room []string
+		want *Room
+		err  error
+	}{
+		{
+			name: "Fails on incorrect capacity format",
+			room: []string{"A-1", "x"},
+			err: &RoomError{
+				Name: "A-1",
+				Err:  &strconv.NumError{Func: "Atoi", Num: "x", Err: strconv.ErrSyntax},
+			},
+		},
+		{
+			name: "Fails on capacity lower than 1",
+			room: []string{"A-1", "0"},
+			err:  &RoomError{Name: "A-1", Err: ErrWrongCapacity},
+		},
+		{
+			name: "Successfully creates room without features",
+			room: []string{"A-1", "30"},
+			want: &Room{Name: "A-1", Capacity: 30},
+		},
+		{
+			name: "Successfully creates room with features",
+			room: []string{"A-1", "16", "computers, projector,"},
+			want: &Room{Name: "A-1", Capacity: 16, Features: []string{"computers", "projector"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRoom(tt.room)
+			if !tools.CompareErrors(err, tt.err) {
+				t.Errorf("NewRoom() error = %v, err %v", err, tt.err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("NewRoom() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Occupancy(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	half := time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC)
+	grs := []*Group{
+		{Name: "1", Type: Class, Weekday: time.Monday, StartTime: ten, EndTime: half, Place: "B-2", Frequency: 2},
+		{Name: "2", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Place: "A-1", Frequency: 1},
+		{Name: "3", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 1},
+	}
+	s := &Schedule{
+		Subjects: []*Subject{{Name: "Math", Groups: grs}},
+		Rooms: []*Room{
+			{Name: "A-1", Capacity: 20},
+			{Name: "C-3", Capacity: 10, Features: []string{"computers"}},
+		},
+	}
+	want := []*Room{
+		{Name: "A-1", Capacity: 20, Meetings: []*Booking{{Subject: "Math", Group: grs[1], Meeting: grs[1]}}},
+		{Name: "B-2", Meetings: []*Booking{{Subject: "Math", Group: grs[0], Meeting: grs[0]}}},
+		{Name: "C-3", Capacity: 10, Features: []string{"computers"}},
+	}
+	if got := s.Occupancy(); !cmp.Equal(got, want) {
+		t.Errorf("Schedule.Occupancy() diff = %s", cmp.Diff(got, want))
+	}
+	if s.Rooms[0].Meetings != nil {
+		t.Errorf("Schedule.Occupancy() modified rooms of a schedule")
+	}
+}
+
+func TestSchedule_ValidateRooms(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	half := time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		groups []*Group
+		err    error
+	}{
+		{
+			name: "Accepts places without colliding groups",
+			groups: []*Group{
+				{Name: "1", Type: Class, Weekday: time.Monday, StartTime: ten, EndTime: half, Place: "A-1", Capacity: 15},
+				{Name: "2", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Place: "A-1", Capacity: 15},
+				{Name: "3", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Capacity: 15},
+			},
+		},
+		{
+			name: "Fails when a room is booked for colliding groups",
+			groups: []*Group{
+				{Name: "1", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Place: "A-1", Capacity: 15},
+				{Name: "2", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Place: "A-1", Capacity: 15},
+			},
+			err: &RoomError{
+				Name: "A-1",
+				Err:  fmt.Errorf("%w: Math 1 and Math 2 on Tuesday", ErrRoomClash),
+			},
+		},
+		{
+			name: "Fails when a place which is not a room is booked for colliding groups",
+			groups: []*Group{
+				{Name: "1", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Place: "online", Capacity: 15},
+				{Name: "2", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Place: "online", Capacity: 15},
+			},
+			err: &RoomError{
+				Name: "online",
+				Err:  fmt.Errorf("%w: Math 1 and Math 2 on Tuesday", ErrRoomClash),
+			},
+		},
+		{
+			name: "Accepts groups in a place which is not a room regardless of their capacity",
+			groups: []*Group{
+				{Name: "1", Type: Class, Weekday: time.Monday, StartTime: ten, EndTime: half, Place: "B-2", Capacity: 100},
+			},
+		},
+		{
+			name: "Fails when capacity of a group exceeds capacity of a room",
+			groups: []*Group{
+				{Name: "1", Type: Class, Weekday: time.Monday, StartTime: ten, EndTime: half, Place: "A-1", Capacity: 25},
+			},
+			err: &RoomError{
+				Name: "A-1",
+				Err:  fmt.Errorf("%w: Math 1 has 25 places, room has 20", ErrRoomCapacityExceeded),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Schedule{
+				Subjects: []*Subject{{Name: "Math", Groups: tt.groups}},
+				Rooms:    []*Room{{Name: "A-1", Capacity: 20}},
+			}
+			if err := s.ValidateRooms(); !tools.CompareErrors(err, tt.err) {
+				t.Errorf("Schedule.ValidateRooms() error = %v, err %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRoom_Save(t *testing.T) {
+	g := &Group{
+		Name:      "1",
+		Type:      Class,
+		Teacher:   "a",
+		Weekday:   time.Monday,
+		StartTime: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC),
+		Place:     "B-2",
+		Frequency: 2,
+		Students:  []*Student{{Name: "x"}},
+	}
+	r := &Room{Name: "B-2", Meetings: []*Booking{{Subject: "Math", Group: g, Meeting: g}}}
+	want := [][]string{
+		{"B-2", "Math", "1", "Class", "Monday", "10:00", "11:30", "a", "2", "1"},
+	}
+	if got := r.Save(); !cmp.Equal(got, want) {
+		t.Errorf("Room.Save() = %v, want %v", got, want)
+	}
+	if got, want := r.Summary(), []string{"B-2", "", "", "0.75"}; !cmp.Equal(got, want) {
+		t.Errorf("Room.Summary() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+package university
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrWrongSatisfactionModel is returned when a passed satisfaction model name is incorrect.
+var ErrWrongSatisfactionModel = errors.New("incorrect satisfaction model, available models: linear, exponential, borda, points")
+
+// defaultDecayRate is used by ExponentialDecay when its rate is not set.
+const defaultDecayRate = 0.5
+
+// SatisfactionModel calculates how much a student is satisfied with a group they received.
+// ranks - priorities which a student set to all groups of a subject.
+// rank - priority of the received group.
+// It returns a value from 0 to 100.
+type SatisfactionModel interface {
+	Satisfaction(ranks []int, rank int) float64
+}
+
+// BidSatisfactionModel is a SatisfactionModel which uses points bid by students instead of priorities.
+// bids - points which a student bid on all groups of a subject.
+// bid - points bid on the received group.
+// It returns a value from 0 to 100.
+type BidSatisfactionModel interface {
+	SatisfactionModel
+	BidSatisfaction(bids []int, bid int) float64
+}
+
+// NewSatisfactionModel returns a satisfaction model with a passed name.
+// It returns ErrWrongSatisfactionModel when the name is incorrect.
+func NewSatisfactionModel(n string) (SatisfactionModel, error) {
+	switch n {
+	case "linear":
+		return &LinearRank{}, nil
+	case "exponential":
+		return &ExponentialDecay{}, nil
+	case "borda":
+		return &BordaCount{}, nil
+	case "points":
+		return &PointsShare{}, nil
+	}
+	return nil, ErrWrongSatisfactionModel
+}
+
+// LinearRank decreases satisfaction linearly with a priority.
+// The most preferred group gives 100, the least preferred one gives 100 divided by the number of priorities.
+type LinearRank struct{}
+
+// Satisfaction implements SatisfactionModel.
+func (l *LinearRank) Satisfaction(ranks []int, rank int) float64 {
+	worst := rank
+	for _, r := range ranks {
+		if r > worst {
+			worst = r
+		}
+	}
+	return float64(worst-rank+1) / float64(worst) * 100.0
+}
+
+// ExponentialDecay multiplies satisfaction by Rate with every next priority.
+// Rate - value from 0 to 1, defaultDecayRate is used when it is out of range.
+type ExponentialDecay struct {
+	Rate float64
+}
+
+// Satisfaction implements SatisfactionModel.
+func (e *ExponentialDecay) Satisfaction(ranks []int, rank int) float64 {
+	r := e.Rate
+	if r <= 0 || r >= 1 {
+		r = defaultDecayRate
+	}
+	return math.Pow(r, float64(rank-1)) * 100.0
+}
+
+// BordaCount gives a point for every group which a student ranked lower than the received one.
+// Points are scaled so that the most preferred group gives 100.
+type BordaCount struct{}
+
+// Satisfaction implements SatisfactionModel.
+func (b *BordaCount) Satisfaction(ranks []int, rank int) float64 {
+	best := rank
+	for _, r := range ranks {
+		if r < best {
+			best = r
+		}
+	}
+	var points, max int
+	for _, r := range ranks {
+		if r > rank {
+			points++
+		}
+		if r > best {
+			max++
+		}
+	}
+	if max == 0 {
+		return 100.0
+	}
+	return float64(points) / float64(max) * 100.0
+}
+
+// PointsShare gives satisfaction proportional to points which a student bid on the received group.
+// The group with the highest bid of a subject gives 100 and a group without a bid gives 0,
+// a student who bid on no group of a subject is satisfied with any of them.
+// Students who ranked groups are satisfied as with LinearRank.
+type PointsShare struct{}
+
+// Satisfaction implements SatisfactionModel.
+func (p *PointsShare) Satisfaction(ranks []int, rank int) float64 {
+	return (&LinearRank{}).Satisfaction(ranks, rank)
+}
+
+// BidSatisfaction implements BidSatisfactionModel.
+func (p *PointsShare) BidSatisfaction(bids []int, bid int) float64 {
+	max := bid
+	for _, b := range bids {
+		if b > max {
+			max = b
+		}
+	}
+	if max == 0 {
+		return 100.0
+	}
+	return float64(bid) / float64(max) * 100.0
+}
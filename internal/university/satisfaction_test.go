@@ -0,0 +1,161 @@
+package university
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestNewSatisfactionModel(t *testing.T) {
+	tests := []struct {
+		name string
+		n    string
+		want SatisfactionModel
+		err  error
+	}{
+		{
+			name: "Returns linear rank model",
+			n:    "linear",
+			want: &LinearRank{},
+		},
+		{
+			name: "Returns exponential decay model",
+			n:    "exponential",
+			want: &ExponentialDecay{},
+		},
+		{
+			name: "Returns Borda count model",
+			n:    "borda",
+			want: &BordaCount{},
+		},
+		{
+			name: "Returns points share model",
+			n:    "points",
+			want: &PointsShare{},
+		},
+		{
+			name: "Fails on incorrect name",
+			n:    "wrong",
+			err:  ErrWrongSatisfactionModel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSatisfactionModel(tt.n)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("NewSatisfactionModel() error = %v, err %v", err, tt.err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("NewSatisfactionModel() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSatisfactionModel_Satisfaction(t *testing.T) {
+	type args struct {
+		ranks []int
+		rank  int
+	}
+	tests := []struct {
+		name string
+		m    SatisfactionModel
+		args args
+		want float64
+	}{
+		{
+			name: "Linear rank gives 100 for the first priority",
+			m:    &LinearRank{},
+			args: args{ranks: []int{1, 2, 3, 4}, rank: 1},
+			want: 100.0,
+		},
+		{
+			name: "Linear rank decreases with priority",
+			m:    &LinearRank{},
+			args: args{ranks: []int{1, 2, 3, 4}, rank: 4},
+			want: 25.0,
+		},
+		{
+			name: "Exponential decay halves satisfaction by default",
+			m:    &ExponentialDecay{},
+			args: args{ranks: []int{1, 2, 3}, rank: 3},
+			want: 25.0,
+		},
+		{
+			name: "Exponential decay uses passed rate",
+			m:    &ExponentialDecay{Rate: 0.8},
+			args: args{ranks: []int{1, 2}, rank: 2},
+			want: 80.0,
+		},
+		{
+			name: "Borda count gives points for groups ranked lower",
+			m:    &BordaCount{},
+			args: args{ranks: []int{1, 2, 2, 3, 4}, rank: 2},
+			want: 50.0,
+		},
+		{
+			name: "Borda count gives 0 for the last priority",
+			m:    &BordaCount{},
+			args: args{ranks: []int{1, 2, 3}, rank: 3},
+		},
+		{
+			name: "Borda count gives 100 when all groups have the same priority",
+			m:    &BordaCount{},
+			args: args{ranks: []int{1, 1}, rank: 1},
+			want: 100.0,
+		},
+		{
+			name: "Points share falls back to linear rank for priorities",
+			m:    &PointsShare{},
+			args: args{ranks: []int{1, 2, 3, 4}, rank: 4},
+			want: 25.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Satisfaction(tt.args.ranks, tt.args.rank); got != tt.want {
+				t.Errorf("SatisfactionModel.Satisfaction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointsShare_BidSatisfaction(t *testing.T) {
+	type args struct {
+		bids []int
+		bid  int
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "Gives 100 for the highest bid",
+			args: args{bids: []int{30, 10, 0}, bid: 30},
+			want: 100.0,
+		},
+		{
+			name: "Gives a share of the highest bid",
+			args: args{bids: []int{40, 10, 0}, bid: 10},
+			want: 25.0,
+		},
+		{
+			name: "Gives 0 for a group without a bid",
+			args: args{bids: []int{40, 10, 0}, bid: 0},
+		},
+		{
+			name: "Gives 100 when a student did not bid in a subject",
+			args: args{bids: []int{0, 0}, bid: 0},
+			want: 100.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&PointsShare{}).BidSatisfaction(tt.args.bids, tt.args.bid); got != tt.want {
+				t.Errorf("PointsShare.BidSatisfaction() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
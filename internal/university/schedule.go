@@ -1,15 +1,34 @@
 // Package university ...
 package university
 
+import (
+	"fmt"
+	"time"
+)
+
 const (
 	timeLayout = "15:04"
 	dateLayout = "01-02-06"
 )
 
 // Schedule represents schedule for one semester.
+// Solver - algorithm used to enroll students, see Enroll.
+// Satisfaction - model used to calculate students' happiness, LinearRank is used when it is not set.
+// Fairness - policy used to distribute happiness between students after solving.
+// Rooms - known rooms with their capacities, places of groups which are not among them have unknown capacity.
+// History - changes of students' groups made after enrollment, see Withdraw.
+// Baseline - previous enrollment whose groups students keep unless a move gives them more happiness than Churn, optional.
+// Churn - penalty in happiness points for every group of a student which differs from the Baseline.
 // It implements sort.Interface based on the number of conflicts in a slice containing subjects.
 type Schedule struct {
-	Subjects []*Subject
+	Subjects     []*Subject
+	Solver       Solver
+	Satisfaction SatisfactionModel
+	Fairness     FairnessPolicy
+	Rooms        []*Room
+	History      []*Event
+	Baseline     *Baseline
+	Churn        float64
 }
 
 func (s *Schedule) Len() int {
@@ -25,7 +44,9 @@ func (s *Schedule) Swap(i, j int) {
 }
 
 // NewSchedule creates new instance of Schedule.
-// It returns GroupError when passed parameters are invalid.
+// Rows with the same subject and group name are meetings of one group, every row after the first one becomes a subgroup.
+// It returns GroupError when passed parameters are invalid, TeacherError when a teacher is booked into colliding groups
+// and RoomError when a place is booked for colliding groups.
 // It receives slice of groups - see NewGroup for description of parameters.
 func NewSchedule(groups [][]string) (*Schedule, error) {
 	s := &Schedule{}
@@ -42,6 +63,10 @@ func NewSchedule(groups [][]string) (*Schedule, error) {
 			s.Subjects = append(s.Subjects, sub)
 		}
 		if ng.Type == Lecture {
+			if l := sub.GetLecture(ng.Name); l != nil {
+				l.SubGroups = append(l.SubGroups, ng)
+				continue
+			}
 			sub.Lectures = append(sub.Lectures, ng)
 			continue
 		}
@@ -51,9 +76,45 @@ func NewSchedule(groups [][]string) (*Schedule, error) {
 		}
 		sub.Groups = append(sub.Groups, ng)
 	}
+	if err := s.ValidateTeachers(); err != nil {
+		return nil, err
+	}
+	if err := s.ValidateRooms(); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
+// ValidateStudents checks if students chose subjects and groups which exist in a schedule.
+// It returns StudentError with ErrUnknownSubject or ErrUnknownGroup for the first invalid student.
+func (s *Schedule) ValidateStudents(students []*Student) error {
+	for _, st := range students {
+		for _, n := range st.Subjects {
+			if s.GetSubject(n) == nil {
+				return &StudentError{Err: fmt.Errorf("%w: %s", ErrUnknownSubject, n), Name: st.Name}
+			}
+		}
+		for k := range st.Preferences {
+			sub := s.GetSubject(k.Subject)
+			if sub == nil {
+				return &StudentError{Err: fmt.Errorf("%w: %s", ErrUnknownSubject, k.Subject), Name: st.Name}
+			}
+			if sub.GetGroup(k.Group) == nil && sub.GetLecture(k.Group) == nil {
+				return &StudentError{Err: fmt.Errorf("%w: %s %s", ErrUnknownGroup, k.Subject, k.Group), Name: st.Name}
+			}
+		}
+	}
+	return nil
+}
+
+// satisfaction returns the satisfaction model of a schedule.
+func (s *Schedule) satisfaction() SatisfactionModel {
+	if s.Satisfaction == nil {
+		return &LinearRank{}
+	}
+	return s.Satisfaction
+}
+
 // GetSubject returns a Subject with a passed name.
 func (s *Schedule) GetSubject(n string) *Subject {
 	for _, sub := range s.Subjects {
@@ -63,3 +124,22 @@ func (s *Schedule) GetSubject(n string) *Subject {
 	}
 	return nil
 }
+
+// ParseDate parses a date in the same format as a start date of a group.
+func ParseDate(d string) (time.Time, error) {
+	return time.Parse(dateLayout, d)
+}
+
+// SetEndDate sets the end of a semester for all groups in a schedule.
+// It is used to calculate dates on which groups meet.
+func (s *Schedule) SetEndDate(d time.Time) {
+	for _, sub := range s.Subjects {
+		for _, gs := range [][]*Group{sub.Lectures, sub.Groups} {
+			for _, g := range gs {
+				for _, m := range g.Meetings() {
+					m.EndDate = d
+				}
+			}
+		}
+	}
+}
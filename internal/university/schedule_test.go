@@ -1,6 +1,7 @@
 package university
 
 import (
+	"fmt"
 	"sort"
 	"testing"
 	"time"
@@ -33,6 +34,32 @@ func TestNewSchedule(t *testing.T) {
 				Err: ErrWrongClassType,
 			},
 		},
+		{
+			name: "Fails when a teacher is booked into colliding groups",
+			args: args{
+				groups: [][]string{
+					{"Programming", "Class", "teacher", "Monday", "14:00", "15:30", "C-2 313", "03-02-20", "1", "1", "15"},
+					{"Math", "Class", "teacher", "Monday", "15:00", "16:30", "C-2 314", "03-02-20", "1", "1", "15"},
+				},
+			},
+			err: &TeacherError{
+				Name: "teacher",
+				Err:  fmt.Errorf("%w: Programming 1 and Math 1 on Monday", ErrTeacherDoubleBooked),
+			},
+		},
+		{
+			name: "Fails when a place is booked for colliding groups",
+			args: args{
+				groups: [][]string{
+					{"Programming", "Class", "a", "Monday", "14:00", "15:30", "online", "03-02-20", "1", "1", "15"},
+					{"Math", "Class", "b", "Monday", "15:00", "16:30", "online", "03-02-20", "1", "1", "15"},
+				},
+			},
+			err: &RoomError{
+				Name: "online",
+				Err:  fmt.Errorf("%w: Programming 1 and Math 1 on Monday", ErrRoomClash),
+			},
+		},
 		{
 			name: "Successfully creates schedule",
 			args: args{
@@ -101,6 +128,50 @@ func TestNewSchedule(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Attaches meetings with the same group name as subgroups",
+			args: args{
+				groups: [][]string{
+					{"Programming", "Class", "teacher", "Monday", "14:00", "15:30", "C-2 313", "03-02-20", "1", "1", "30"},
+					{"Programming", "Class", "teacher", "Thursday", "8:00", "9:30", "C-2 313", "03-05-20", "1", "1", "30"},
+				},
+			},
+			want: &Schedule{
+				Subjects: []*Subject{
+					{
+						Name: "Programming",
+						Groups: []*Group{
+							{
+								Type:      Class,
+								Teacher:   "teacher",
+								Weekday:   time.Monday,
+								StartTime: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC),
+								EndTime:   time.Date(0, 1, 1, 15, 30, 0, 0, time.UTC),
+								Place:     "C-2 313",
+								StartDate: time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+								Frequency: 1,
+								Name:      "1",
+								Capacity:  30,
+								SubGroups: []*Group{
+									{
+										Type:      Class,
+										Teacher:   "teacher",
+										Weekday:   time.Thursday,
+										StartTime: time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+										EndTime:   time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC),
+										Place:     "C-2 313",
+										StartDate: time.Date(2020, 3, 5, 0, 0, 0, 0, time.UTC),
+										Frequency: 1,
+										Name:      "1",
+										Capacity:  30,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -343,3 +414,117 @@ func TestSortSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestSchedule_SetEndDate(t *testing.T) {
+	end := time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC)
+	s := &Schedule{
+		Subjects: []*Subject{
+			{
+				Lectures: []*Group{
+					{Name: "Lecture"},
+				},
+				Groups: []*Group{
+					{
+						Name: "1",
+						SubGroups: []*Group{
+							{Name: "1"},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.SetEndDate(end)
+	sub := s.Subjects[0]
+	for _, g := range []*Group{sub.Lectures[0], sub.Groups[0], sub.Groups[0].SubGroups[0]} {
+		if !g.EndDate.Equal(end) {
+			t.Errorf("Schedule.SetEndDate() got = %v, want %v", g.EndDate, end)
+		}
+	}
+}
+
+func TestSchedule_ValidateStudents(t *testing.T) {
+	s := &Schedule{
+		Subjects: []*Subject{
+			{
+				Name: "Math",
+				Lectures: []*Group{
+					{Name: "Lecture"},
+				},
+				Groups: []*Group{
+					{Name: "1"},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		students []*Student
+		err      error
+	}{
+		{
+			name: "Successfully validates students",
+			students: []*Student{
+				{
+					Name:     "a",
+					Subjects: []string{"Math"},
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}:       1,
+						{"Math", "Lecture"}: 1,
+					},
+				},
+			},
+		},
+		{
+			name: "Fails on unknown subject in a subject list",
+			students: []*Student{
+				{
+					Name:     "a",
+					Subjects: []string{"Physics"},
+				},
+			},
+			err: &StudentError{
+				Name: "a",
+				Err:  fmt.Errorf("%w: Physics", ErrUnknownSubject),
+			},
+		},
+		{
+			name: "Fails on unknown subject in preferences",
+			students: []*Student{
+				{
+					Name: "a",
+					Preferences: map[SubjectGroup]int{
+						{"Physics", "1"}: 1,
+					},
+				},
+			},
+			err: &StudentError{
+				Name: "a",
+				Err:  fmt.Errorf("%w: Physics", ErrUnknownSubject),
+			},
+		},
+		{
+			name: "Fails on unknown group",
+			students: []*Student{
+				{
+					Name: "a",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "2"}: 1,
+					},
+				},
+			},
+			err: &StudentError{
+				Name: "a",
+				Err:  fmt.Errorf("%w: Math 2", ErrUnknownGroup),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateStudents(tt.students)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("Schedule.ValidateStudents() error = %v, err %v", err, tt.err)
+			}
+		})
+	}
+}
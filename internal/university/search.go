@@ -0,0 +1,363 @@
+package university
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// defaultSearchIterations is the number of improvement passes used when SearchSolver.Iterations is not set.
+	defaultSearchIterations = 50
+	// unassignedCost is the cost of leaving a student without a group, it is higher than the cost of any group.
+	unassignedCost = 100.0
+	// searchEpsilon is the smallest decrease of cost which is treated as an improvement.
+	searchEpsilon = 1e-9
+	// flowScale converts costs of groups to integer costs of edges in a flow network.
+	flowScale = 1e6
+)
+
+// SearchSolver models the whole schedule at once.
+// Every student receives a timetable without collisions across all subjects, capacities of groups are never exceeded
+// and the mean happiness of students according to the Satisfaction model of a schedule is maximized.
+// Happiness in every subject is divided by the number of subjects of a student, like in Student.GetHappiness.
+// Students are first assigned subject by subject with a minimum cost flow, like in FlowSolver, and then improved
+// with local search as long as the total cost decreases: a timetable of every student is re-optimized with
+// branch and bound and all students of every subject are assigned again with a minimum cost flow.
+// Iterations - maximum number of improvement passes.
+type SearchSolver struct {
+	Iterations int
+}
+
+// searchState holds the current assignment of a SearchSolver.
+type searchState struct {
+	subjects []*Subject
+	students []*Student
+	// groups contains all groups of subjects, subjects[k] groups are groups[offset[k]:offset[k+1]]
+	groups  []*Group
+	offset  []int
+	collide [][]bool
+	free    []int
+	// costs[i][j] is the cost of assigning student i to group j, it is set only for subjects which student i takes
+	costs [][]float64
+	// assigned[i][k] is an index of a group in groups or -1 when student i has no group for subject k
+	assigned [][]int
+}
+
+// Solve implements Solver.
+func (ss *SearchSolver) Solve(s *Schedule, students []*Student) {
+	s.assignLectures(students)
+	st := newSearchState(s, students)
+	for k := range st.subjects {
+		st.reassign(k)
+	}
+	it := ss.Iterations
+	if it <= 0 {
+		it = defaultSearchIterations
+	}
+	for ; it > 0; it-- {
+		improved := false
+		for i := range st.students {
+			old := st.cost(i, st.assigned[i])
+			st.unassign(i)
+			b := st.best(i)
+			if st.cost(i, b) < old-searchEpsilon {
+				improved = true
+			}
+			st.assign(i, b)
+		}
+		for k := range st.subjects {
+			if st.reassign(k) {
+				improved = true
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	st.save()
+}
+
+func newSearchState(s *Schedule, students []*Student) *searchState {
+	st := &searchState{}
+	m := s.satisfaction()
+	for _, sub := range s.Subjects {
+		if len(sub.Groups) == 0 {
+			continue
+		}
+		st.subjects = append(st.subjects, sub)
+		st.offset = append(st.offset, len(st.groups))
+		st.groups = append(st.groups, sub.Groups...)
+	}
+	st.offset = append(st.offset, len(st.groups))
+	st.collide = make([][]bool, len(st.groups))
+	for i, a := range st.groups {
+		st.collide[i] = make([]bool, len(st.groups))
+		for j, b := range st.groups {
+			st.collide[i][j] = i != j && a.Collide(b)
+		}
+	}
+	st.free = make([]int, len(st.groups))
+	for i, g := range st.groups {
+		st.free[i] = g.Capacity - len(g.PriorityStudents) - len(g.Students)
+	}
+	for _, s := range students {
+		if !s.Priority {
+			st.students = append(st.students, s)
+			continue
+		}
+		// Priority students receive their preferred groups
+		for k, sub := range st.subjects {
+			if !s.Enrolled(sub.Name) {
+				continue
+			}
+			g := sub.GetGroup(s.GetPreferredGroup(sub.Name, sub.GetGroupsNames()))
+			if g == nil {
+				continue
+			}
+			g.PriorityStudents = append(g.PriorityStudents, s)
+			s.FinalGroups[sub.Name] = g
+			st.free[st.index(k, g)]--
+		}
+	}
+	st.costs = make([][]float64, len(st.students))
+	for i, s := range st.students {
+		st.costs[i] = make([]float64, len(st.groups))
+		n := 0
+		for _, sub := range st.subjects {
+			if s.Enrolled(sub.Name) {
+				n++
+			}
+		}
+		for k, sub := range st.subjects {
+			if !st.takes(i, k) {
+				continue
+			}
+			for j := st.offset[k]; j < st.offset[k+1]; j++ {
+				st.costs[i][j] = -s.happiness(m, sub, st.groups[j]) / float64(n)
+			}
+		}
+	}
+	st.assigned = make([][]int, len(st.students))
+	for i := range st.assigned {
+		st.assigned[i] = make([]int, len(st.subjects))
+		for k := range st.assigned[i] {
+			st.assigned[i][k] = -1
+		}
+	}
+	return st
+}
+
+// index returns an index of a group in groups.
+func (st *searchState) index(k int, g *Group) int {
+	for j := st.offset[k]; j < st.offset[k+1]; j++ {
+		if st.groups[j] == g {
+			return j
+		}
+	}
+	return -1
+}
+
+// takes checks if student i is enrolled in subject k and ranked any of its groups.
+func (st *searchState) takes(i, k int) bool {
+	return st.students[i].Enrolled(st.subjects[k].Name) && st.students[i].ranks(st.subjects[k])
+}
+
+// groupCost returns the cost of assigning student i to group j of subject k, which is their negated happiness.
+// Not assigning a student costs more than assigning them to any group, unless they do not take a subject, see takes.
+func (st *searchState) groupCost(i, k, j int) float64 {
+	if j < 0 && !st.takes(i, k) {
+		return 0
+	}
+	if j < 0 {
+		return unassignedCost
+	}
+	return st.costs[i][j]
+}
+
+// cost returns the cost of a timetable of student i.
+func (st *searchState) cost(i int, a []int) (res float64) {
+	for k, j := range a {
+		res += st.groupCost(i, k, j)
+	}
+	return
+}
+
+func (st *searchState) assign(i int, a []int) {
+	st.assigned[i] = a
+	for _, j := range a {
+		if j >= 0 {
+			st.free[j]--
+		}
+	}
+}
+
+func (st *searchState) unassign(i int) {
+	for k, j := range st.assigned[i] {
+		if j >= 0 {
+			st.free[j]++
+		}
+		st.assigned[i][k] = -1
+	}
+}
+
+// best finds the cheapest timetable of student i with branch and bound.
+// Only groups with free places which do not collide with student's lectures are taken into account.
+func (st *searchState) best(i int) []int {
+	// Subjects with the fewest options are chosen first to prune the search early
+	order := make([]int, len(st.subjects))
+	options := make([][]int, len(st.subjects))
+	for k := range st.subjects {
+		order[k] = k
+		if !st.takes(i, k) {
+			options[k] = []int{-1}
+			continue
+		}
+		for j := st.offset[k]; j < st.offset[k+1]; j++ {
+			if st.free[j] > 0 && st.students[i].CanMove(st.subjects[k].Name, st.groups[j]) {
+				options[k] = append(options[k], j)
+			}
+		}
+		sort.SliceStable(options[k], func(a, b int) bool {
+			return st.groupCost(i, k, options[k][a]) < st.groupCost(i, k, options[k][b])
+		})
+		options[k] = append(options[k], -1)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(options[order[a]]) < len(options[order[b]])
+	})
+	// bound[d] is the lowest possible cost of subjects order[d:]
+	bound := make([]float64, len(order)+1)
+	for d := len(order) - 1; d >= 0; d-- {
+		k := order[d]
+		bound[d] = bound[d+1] + st.groupCost(i, k, options[k][0])
+	}
+
+	cur := make([]int, len(st.subjects))
+	res := make([]int, len(st.subjects))
+	var best float64
+	found := false
+	var search func(d int, c float64)
+	search = func(d int, c float64) {
+		if found && c+bound[d] >= best-searchEpsilon {
+			return
+		}
+		if d == len(order) {
+			best, found = c, true
+			copy(res, cur)
+			return
+		}
+		k := order[d]
+		for _, j := range options[k] {
+			if j >= 0 && st.collides(j, cur, order[:d]) {
+				continue
+			}
+			cur[k] = j
+			search(d+1, c+st.groupCost(i, k, j))
+		}
+	}
+	search(0, 0)
+	return res
+}
+
+// collides checks if group j collides with groups chosen for subjects ks.
+func (st *searchState) collides(j int, a []int, ks []int) bool {
+	for _, k := range ks {
+		if a[k] >= 0 && st.collide[j][a[k]] {
+			return true
+		}
+	}
+	return false
+}
+
+// canTake checks if student i can attend group j instead of their group for subject k.
+func (st *searchState) canTake(i, k, j int) bool {
+	for l, g := range st.assigned[i] {
+		if l != k && g >= 0 && st.collide[j][g] {
+			return false
+		}
+	}
+	return true
+}
+
+// reassign assigns all students who take subject k again with a minimum cost flow, groups of other subjects are kept.
+// The new assignment is used only when it decreases the total cost, it returns true in such case.
+func (st *searchState) reassign(k int) bool {
+	var sts []int
+	old := 0.0
+	for i := range st.students {
+		if st.takes(i, k) {
+			sts = append(sts, i)
+			old += st.groupCost(i, k, st.assigned[i][k])
+		}
+	}
+	first, gs := st.offset[k], st.offset[k+1]-st.offset[k]
+	// Nodes: source, students, groups, sink
+	src, sink := 0, len(sts)+gs+1
+	n := newFlowNetwork(sink + 1)
+	caps := make([]int, gs)
+	for x := range caps {
+		if f := st.free[first+x]; f > 0 {
+			caps[x] = f
+		}
+	}
+	edges := make([][]int, len(sts))
+	for y, i := range sts {
+		if j := st.assigned[i][k]; j >= 0 {
+			caps[j-first]++
+		}
+		n.addEdge(src, y+1, 1, 0)
+		edges[y] = make([]int, gs)
+		for x := range edges[y] {
+			j := first + x
+			edges[y][x] = -1
+			if !st.canTake(i, k, j) || !st.students[i].CanMove(st.subjects[k].Name, st.groups[j]) {
+				continue
+			}
+			edges[y][x] = n.addEdge(y+1, len(sts)+x+1, 1, int(math.Round(st.groupCost(i, k, j)*flowScale)))
+		}
+	}
+	for x, c := range caps {
+		n.addEdge(len(sts)+x+1, sink, c, 0)
+	}
+	n.minCostFlow(src, sink)
+
+	res := make([]int, len(sts))
+	cost := 0.0
+	for y, i := range sts {
+		res[y] = -1
+		for x, e := range edges[y] {
+			if e >= 0 && n.edges[e].flow > 0 {
+				res[y] = first + x
+			}
+		}
+		cost += st.groupCost(i, k, res[y])
+	}
+	if cost >= old-searchEpsilon {
+		return false
+	}
+	for y, i := range sts {
+		if j := st.assigned[i][k]; j >= 0 {
+			st.free[j]++
+		}
+		if j := res[y]; j >= 0 {
+			st.free[j]--
+		}
+		st.assigned[i][k] = res[y]
+	}
+	return true
+}
+
+// save moves the found assignment to groups and students.
+func (st *searchState) save() {
+	for i, s := range st.students {
+		for k, sub := range st.subjects {
+			j := st.assigned[i][k]
+			if j < 0 {
+				continue
+			}
+			g := st.groups[j]
+			g.Students = append(g.Students, s)
+			s.FinalGroups[sub.Name] = g
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package university
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchSolver_Solve(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		subjects []*Subject
+		students []*Student
+		want     map[string]map[string]string
+	}{
+		{
+			name: "Avoids collisions across subjects",
+			subjects: []*Subject{
+				{
+					Name: "Math",
+					Groups: []*Group{
+						{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+						{Name: "2", Capacity: 1, Weekday: time.Tuesday, StartTime: ten},
+					},
+				},
+				{
+					Name: "Physics",
+					Groups: []*Group{
+						{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+					},
+				},
+			},
+			students: []*Student{
+				{
+					Name: "a",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}:    1,
+						{"Math", "2"}:    2,
+						{"Physics", "1"}: 1,
+					},
+				},
+			},
+			want: map[string]map[string]string{
+				"a": {
+					"Math":    "2",
+					"Physics": "1",
+				},
+			},
+		},
+		{
+			name: "Does not exceed capacity and keeps groups of priority students",
+			subjects: []*Subject{
+				{
+					Name: "Math",
+					Groups: []*Group{
+						{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+						{Name: "2", Capacity: 1, Weekday: time.Tuesday, StartTime: ten},
+					},
+				},
+			},
+			students: []*Student{
+				{
+					Name: "a",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}: 1,
+						{"Math", "2"}: 2,
+					},
+				},
+				{
+					Name:     "b",
+					Priority: true,
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}: 1,
+						{"Math", "2"}: 2,
+					},
+				},
+				{
+					Name: "c",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}: 1,
+						{"Math", "2"}: 2,
+					},
+				},
+			},
+			want: map[string]map[string]string{
+				"a": {
+					"Math": "2",
+				},
+				"b": {
+					"Math": "1",
+				},
+				"c": {
+					"Math": "",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, st := range tt.students {
+				st.Happiness = make(map[string]float64)
+				st.FinalGroups = make(map[string]*Group)
+			}
+			(&SearchSolver{}).Solve(&Schedule{Subjects: tt.subjects}, tt.students)
+			for _, st := range tt.students {
+				for sub, want := range tt.want[st.Name] {
+					var got string
+					if g := st.FinalGroups[sub]; g != nil {
+						got = g.Name
+					}
+					if got != want {
+						t.Errorf("SearchSolver.Solve() student %s, subject %s got = %v, want %v", st.Name, sub, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSearchSolver_SolveComparedWithFlow(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		satisfaction SatisfactionModel
+		subjects     []*Subject
+		students     []*Student
+	}{
+		{
+			name:         "Moves a student to another group to attend a colliding subject",
+			satisfaction: &BordaCount{},
+			subjects: []*Subject{
+				{
+					Name: "Math",
+					Groups: []*Group{
+						{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+						{Name: "2", Capacity: 1, Weekday: time.Tuesday, StartTime: ten},
+						{Name: "3", Capacity: 1, Weekday: time.Wednesday, StartTime: ten},
+					},
+				},
+				{
+					Name: "Physics",
+					Groups: []*Group{
+						{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+					},
+				},
+			},
+			students: []*Student{
+				{
+					Name: "a",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}:    1,
+						{"Math", "2"}:    2,
+						{"Math", "3"}:    3,
+						{"Physics", "1"}: 1,
+					},
+				},
+			},
+		},
+		{
+			name:         "Gives the preferred group to a student with fewer subjects",
+			satisfaction: &LinearRank{},
+			subjects: []*Subject{
+				{
+					Name: "Math",
+					Groups: []*Group{
+						{Name: "1", Capacity: 1, Weekday: time.Monday, StartTime: ten},
+						{Name: "2", Capacity: 1, Weekday: time.Tuesday, StartTime: ten},
+					},
+				},
+				{
+					Name: "Physics",
+					Groups: []*Group{
+						{Name: "1", Capacity: 1, Weekday: time.Friday, StartTime: ten},
+					},
+				},
+			},
+			students: []*Student{
+				{
+					Name: "a",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}:    1,
+						{"Math", "2"}:    2,
+						{"Physics", "1"}: 1,
+					},
+				},
+				{
+					Name: "b",
+					Preferences: map[SubjectGroup]int{
+						{"Math", "1"}: 1,
+						{"Math", "2"}: 2,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mean := func(sv Solver) float64 {
+				for _, sub := range tt.subjects {
+					for _, g := range sub.Groups {
+						g.Students = nil
+					}
+				}
+				for _, st := range tt.students {
+					st.Happiness = make(map[string]float64)
+					st.FinalGroups = make(map[string]*Group)
+				}
+				s := &Schedule{Subjects: tt.subjects, Solver: sv, Satisfaction: tt.satisfaction}
+				res, err := s.Enroll(tt.students)
+				if err != nil {
+					t.Fatalf("Schedule.Enroll() error = %v", err)
+				}
+				return res.Fairness.Mean
+			}
+			flow, search := mean(&FlowSolver{}), mean(&SearchSolver{})
+			if search <= flow {
+				t.Errorf("SearchSolver.Solve() happiness = %v, want more than FlowSolver.Solve() happiness %v", search, flow)
+			}
+		})
+	}
+}
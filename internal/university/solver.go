@@ -0,0 +1,44 @@
+package university
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrWrongSolver is returned when a passed solver name is incorrect.
+var ErrWrongSolver = errors.New("incorrect solver, available solvers: greedy, flow, search, bidding")
+
+// Solver assigns students to groups within a schedule.
+// After solving each student has FinalGroups set for every subject, students without a group have nil.
+// Students who did not rank any group of a subject are not assigned to its groups.
+type Solver interface {
+	Solve(s *Schedule, students []*Student)
+}
+
+// NewSolver returns a solver with a passed name.
+// It returns ErrWrongSolver when the name is incorrect.
+func NewSolver(n string) (Solver, error) {
+	switch n {
+	case "greedy":
+		return &GreedySolver{}, nil
+	case "flow":
+		return &FlowSolver{}, nil
+	case "search":
+		return &SearchSolver{}, nil
+	case "bidding":
+		return &BiddingSolver{}, nil
+	}
+	return nil, ErrWrongSolver
+}
+
+// GreedySolver assigns every student to their preferred group and then
+// moves students from overcrowded groups to the next groups of a subject.
+type GreedySolver struct{}
+
+// Solve implements Solver.
+func (g *GreedySolver) Solve(s *Schedule, students []*Student) {
+	s.assign(students)
+	// Sort subjects by number of conflicts
+	sort.Sort(s)
+	s.resolve(students)
+}
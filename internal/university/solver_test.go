@@ -0,0 +1,95 @@
+package university
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestNewSolver(t *testing.T) {
+	tests := []struct {
+		name string
+		n    string
+		want Solver
+		err  error
+	}{
+		{
+			name: "Returns greedy solver",
+			n:    "greedy",
+			want: &GreedySolver{},
+		},
+		{
+			name: "Returns flow solver",
+			n:    "flow",
+			want: &FlowSolver{},
+		},
+		{
+			name: "Returns search solver",
+			n:    "search",
+			want: &SearchSolver{},
+		},
+		{
+			name: "Returns bidding solver",
+			n:    "bidding",
+			want: &BiddingSolver{},
+		},
+		{
+			name: "Fails on incorrect name",
+			n:    "wrong",
+			err:  ErrWrongSolver,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSolver(tt.n)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("NewSolver() error = %v, err %v", err, tt.err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("NewSolver() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreedySolver_Solve(t *testing.T) {
+	s := &Schedule{
+		Subjects: []*Subject{
+			{
+				Name: "Math",
+				Groups: []*Group{
+					{Name: "1", Capacity: 1, Weekday: 1},
+					{Name: "2", Capacity: 2, Weekday: 2},
+				},
+			},
+		},
+	}
+	students := []*Student{
+		newTestStudent("a", map[string]int{"1": 1, "2": 2}),
+		newTestStudent("b", map[string]int{"1": 1, "2": 2}),
+	}
+	(&GreedySolver{}).Solve(s, students)
+	for _, st := range students {
+		if st.FinalGroups["Math"] == nil {
+			t.Errorf("GreedySolver.Solve() did not assign student %s", st.Name)
+		}
+	}
+	if c := s.Subjects[0].Conflicts(); c > 0 {
+		t.Errorf("GreedySolver.Solve() left %d conflicts", c)
+	}
+}
+
+// newTestStudent creates a student with preferences for groups of the Math subject.
+func newTestStudent(n string, pref map[string]int) *Student {
+	st := &Student{
+		Name:        n,
+		Preferences: make(map[SubjectGroup]int),
+		Happiness:   make(map[string]float64),
+		FinalGroups: make(map[string]*Group),
+	}
+	for g, p := range pref {
+		st.Preferences[SubjectGroup{"Math", g}] = p
+	}
+	return st
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,6 +15,14 @@ var (
 	ErrWrongPriority = errors.New("incorrect priority value: priorities have to start from 1")
 	// ErrWrongSubPriority is returned when priorities for one of a subjects are not consecutive with repetition.
 	ErrWrongSubPriority = errors.New("incorrect priority for subject: priorities have to be consecutive with repetition")
+	// ErrUnknownSubject is returned when a student chose a subject which does not exist in a schedule.
+	ErrUnknownSubject = errors.New("unknown subject")
+	// ErrUnknownGroup is returned when a student set a priority to a group which does not exist in a schedule.
+	ErrUnknownGroup = errors.New("unknown group")
+	// ErrWrongBid is returned when a student bid less than 1 point on a group.
+	ErrWrongBid = errors.New("incorrect bid: a student has to bid at least 1 point on a group")
+	// ErrBudgetExceeded is returned when a student bid more points than their budget.
+	ErrBudgetExceeded = errors.New("bids exceed the budget of a student")
 )
 
 // StudentError represents an error struct returned when creating new Student.
@@ -27,20 +36,26 @@ func (e *StudentError) Error() string {
 }
 
 // Student represents a university student and their preferences.
-// Name - student name which is read from file name with preferences.
+// Name - student name which is read from file name with preferences or set when preferences are submitted.
 // Priority - if set to true, then a student will receive the same schedule as in ChosenGroups.
 // Happiness - reflects how much the final schedule is similar to their preferences.
 // It contains a map in which a key is the subject name and value is calculated happiness.
 // Preferences - contains list of groups with priorities for each subject, one priority for group.
 // - Priorities have to start from 1 (highest).
 // - Priorities have to be consecutive and they can be repeated.
+// Bids - points which a student bid on groups, set only in the bidding mode, see NewBiddingStudent.
 // FinalGroups - groups to which student is assigned after scheduling.
+// FinalLectures - lecture sections to which student is assigned after scheduling.
+// Subjects - subjects in which student is enrolled, if not set student is enrolled in subjects for which they set priorities.
 type Student struct {
-	Name        string
-	Priority    bool
-	Preferences map[SubjectGroup]int
-	Happiness   map[string]float64
-	FinalGroups map[string]*Group
+	Name          string
+	Priority      bool
+	Subjects      []string
+	Preferences   map[SubjectGroup]int
+	Bids          map[SubjectGroup]int
+	Happiness     map[string]float64
+	FinalGroups   map[string]*Group
+	FinalLectures map[string]*Group
 }
 
 // SubjectGroup is used as a key in Preferences.
@@ -53,17 +68,25 @@ type SubjectGroup struct {
 // NewStudent creates a new instance of Student.
 // It returns StudentError when passed parameters are invalid.
 // Passed parameters:
-// n - filename which contains student name
+// n - filename which contains student name, the extension is removed
 // pref:
 // 0 - subject name
 // 1 - group name
 // 2 - group priority
 func NewStudent(pref [][]string, n string) (*Student, error) {
+	return NewNamedStudent(pref, strings.TrimSuffix(n, filepath.Ext(n)))
+}
+
+// NewNamedStudent creates a new instance of Student with name n, which is used as it is.
+// Preferences are passed in the same format as to NewStudent.
+// It returns StudentError when passed parameters are invalid.
+func NewNamedStudent(pref [][]string, n string) (*Student, error) {
 	s := &Student{
-		Name:        strings.Replace(n, ".xlsx", "", -1),
-		Preferences: make(map[SubjectGroup]int),
-		Happiness:   make(map[string]float64),
-		FinalGroups: make(map[string]*Group),
+		Name:          n,
+		Preferences:   make(map[SubjectGroup]int),
+		Happiness:     make(map[string]float64),
+		FinalGroups:   make(map[string]*Group),
+		FinalLectures: make(map[string]*Group),
 	}
 	for _, p := range pref {
 		pr, err := strconv.Atoi(p[2])
@@ -98,12 +121,31 @@ func (s *Student) validate() error {
 	return nil
 }
 
+// Enrolled checks if a student is enrolled in a subject.
+func (s *Student) Enrolled(sub string) bool {
+	if s.Subjects != nil {
+		for _, n := range s.Subjects {
+			if n == sub {
+				return true
+			}
+		}
+		return false
+	}
+	for k := range s.Preferences {
+		if k.Subject == sub {
+			return true
+		}
+	}
+	return false
+}
+
 // GetPreferredGroup returns a name of the group to which student wants to be assigned the most.
+// Groups without a priority are skipped, an empty name is returned when a student did not rank any of groups.
 func (s *Student) GetPreferredGroup(subject string, groups []string) (res string) {
 	p := math.MaxInt64
 	for _, g := range groups {
 		v := s.Preferences[SubjectGroup{subject, g}]
-		if p > v {
+		if v > 0 && p > v {
 			p = v
 			res = g
 		}
@@ -111,6 +153,21 @@ func (s *Student) GetPreferredGroup(subject string, groups []string) (res string
 	return
 }
 
+// ranks checks if a student set a priority to any group of a subject.
+func (s *Student) ranks(sub *Subject) bool {
+	for _, g := range sub.Groups {
+		if s.ranked(sub, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// ranked checks if a student set a priority to group g of a subject.
+func (s *Student) ranked(sub *Subject, g *Group) bool {
+	return s.Preferences[SubjectGroup{sub.Name, g.Name}] > 0
+}
+
 // SetFinalGroup sets a group to which student was assigned.
 func (s *Student) SetFinalGroup(sub *Subject) {
 	s.FinalGroups[sub.Name] = sub.GetStudentGroup(s.Name)
@@ -121,16 +178,69 @@ func (s *Student) Likes(sub, gn string) bool {
 	return s.Preferences[SubjectGroup{sub, gn}] == 1
 }
 
-// CanMove checks if a student can be moved to the other group.
+// CanMove checks if a student can be moved to the other group of a subject.
+// The current group of the subject is not taken into account.
 func (s *Student) CanMove(sub string, g *Group) bool {
+	for k, fg := range s.FinalGroups {
+		if k != sub && fg != nil && g.Collide(fg) {
+			return false
+		}
+	}
+	for _, l := range s.FinalLectures {
+		if l != nil && g.Collide(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// canAttendLecture checks if a lecture section does not collide with other lectures and with groups of a student.
+func (s *Student) canAttendLecture(sub string, l *Group) bool {
+	for k, fl := range s.FinalLectures {
+		if k != sub && fl != nil && l.Collide(fl) {
+			return false
+		}
+	}
 	for _, fg := range s.FinalGroups {
-		if fg != nil && g.Collide(fg) {
+		if fg != nil && l.Collide(fg) {
 			return false
 		}
 	}
 	return true
 }
 
+// unassignedReason returns the reason why a student has no group or lecture section of a subject.
+func (s *Student) unassignedReason(sub *Subject, groups []*Group) UnassignedReason {
+	var pref bool
+	for k := range s.Preferences {
+		if k.Subject == sub.Name {
+			pref = true
+			break
+		}
+	}
+	if !pref || (groups[0].Type != Lecture && !s.ranks(sub)) {
+		return ReasonNoPreference
+	}
+	var canMove, free bool
+	for _, g := range groups {
+		if g.Type == Lecture && s.canAttendLecture(sub.Name, g) {
+			return ReasonFull
+		}
+		if g.Type != Lecture && s.CanMove(sub.Name, g) {
+			canMove = true
+			// Some solvers place students only in groups they ranked
+			free = free || (!s.ranked(sub, g) && g.Conflicts() < 0)
+		}
+	}
+	if free {
+		return ReasonRankedFull
+	}
+	if canMove {
+		return ReasonFull
+	}
+	return ReasonCollision
+}
+
 // GetHappiness is used to retrieve student's happiness
 func (s *Student) GetHappiness() (res float64) {
 	for _, v := range s.Happiness {
@@ -140,50 +250,80 @@ func (s *Student) GetHappiness() (res float64) {
 }
 
 // CalculateHappiness is used to count student's happiness for a subject.
-// It's based on their preferences.
-func (s *Student) CalculateHappiness(sn string) {
-	// Count number of distinct priorities within one subject
-	var priorities []int
-	for k, v := range s.Preferences {
-		if k.Subject == sn {
-			priorities = append(priorities, v)
-		}
+// It's based on the priority which a student set to their final group, students without a group have 0 happiness.
+// Points bid on groups are used instead of priorities when a student bid and m is a BidSatisfactionModel.
+func (s *Student) CalculateHappiness(m SatisfactionModel, sub *Subject) {
+	g := s.FinalGroups[sub.Name]
+	if g == nil {
+		s.Happiness[sub.Name] = 0
+		return
 	}
-	encountered := map[int]bool{}
-	distinct := []int{}
-	for _, p := range priorities {
-		if !encountered[p] {
-			encountered[p] = true
-			distinct = append(distinct, p)
+	s.Happiness[sub.Name] = s.happiness(m, sub, g)
+}
+
+// happiness returns happiness of a student in group g of a subject, see CalculateHappiness.
+func (s *Student) happiness(m SatisfactionModel, sub *Subject, g *Group) float64 {
+	if bm, ok := m.(BidSatisfactionModel); ok && s.Bids != nil {
+		bids := make([]int, len(sub.Groups))
+		for i, sg := range sub.Groups {
+			bids[i] = s.Bids[SubjectGroup{sub.Name, sg.Name}]
 		}
+		return bm.BidSatisfaction(bids, s.Bids[SubjectGroup{sub.Name, g.Name}])
 	}
-
-	if len(distinct) == 1 {
-		s.Happiness[sn] = 100.0
-		return
+	ranks := make([]int, len(sub.Groups))
+	for i, sg := range sub.Groups {
+		ranks[i] = rank(s, sub, sg)
 	}
-	s.Happiness[sn] = (1.0 / (float64(len(distinct)))) * 100.0
+	return m.Satisfaction(ranks, rank(s, sub, g))
 }
 
-// Save creates a slice with groups which were chosen for a student.
+// Save creates a slice with groups and lecture sections which were chosen for a student.
+// Each row describes one meeting: subject name, group name, class type, weekday, start time, end time and place.
+// Rows are sorted by subject name.
 func (s *Student) Save() [][]string {
-	var i int
-	var gLen int
-	for _, v := range s.FinalGroups {
-		if v != nil {
-			gLen++
-		}
-	}
-	res := make([][]string, gLen)
-	for k, v := range s.FinalGroups {
-		if v == nil {
-			continue
-		}
-		r := make([]string, 2)
-		r[0] = k
-		r[1] = v.Name
-		res[i] = r
-		i++
+	var subs []string
+	for k := range s.FinalGroups {
+		subs = append(subs, k)
+	}
+	for k := range s.FinalLectures {
+		if _, ok := s.FinalGroups[k]; !ok {
+			subs = append(subs, k)
+		}
+	}
+	sort.Strings(subs)
+	var res [][]string
+	for _, sub := range subs {
+		for _, g := range []*Group{s.FinalLectures[sub], s.FinalGroups[sub]} {
+			if g == nil {
+				continue
+			}
+			for _, m := range g.Meetings() {
+				res = append(res, []string{
+					sub,
+					g.Name,
+					string(g.Type),
+					m.Weekday.String(),
+					m.StartTime.Format(timeLayout),
+					m.EndTime.Format(timeLayout),
+					m.Place,
+				})
+			}
+		}
+	}
+	return res
+}
+
+// Timetable creates a slice with meetings of groups and lecture sections which were chosen for a student.
+// Each row contains student name, the columns of Save and the priority which a student set to a group,
+// the priority is empty when a student did not rank a group.
+func (s *Student) Timetable() [][]string {
+	res := s.Save()
+	for i, r := range res {
+		var rank string
+		if p := s.Preferences[SubjectGroup{r[0], r[1]}]; p > 0 {
+			rank = strconv.Itoa(p)
+		}
+		res[i] = append(append([]string{s.Name}, r...), rank)
 	}
 	return res
 }
@@ -57,8 +57,9 @@ func TestNewStudent(t *testing.T) {
 					{"subject2", "g1"}: 1,
 					{"subject2", "g2"}: 2,
 				},
-				FinalGroups: make(map[string]*Group),
-				Happiness:   make(map[string]float64),
+				FinalGroups:   make(map[string]*Group),
+				FinalLectures: make(map[string]*Group),
+				Happiness:     make(map[string]float64),
 			},
 		},
 	}
@@ -75,6 +76,27 @@ func TestNewStudent(t *testing.T) {
 	}
 }
 
+func TestNewNamedStudent(t *testing.T) {
+	got, err := NewNamedStudent([][]string{{"subject1", "g1", "1"}}, "j.doe")
+	if err != nil {
+		t.Fatalf("NewNamedStudent() error = %v", err)
+	}
+	want := &Student{
+		Name:          "j.doe",
+		Preferences:   map[SubjectGroup]int{{"subject1", "g1"}: 1},
+		FinalGroups:   make(map[string]*Group),
+		FinalLectures: make(map[string]*Group),
+		Happiness:     make(map[string]float64),
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("NewNamedStudent() got = %v, want %v", got, want)
+	}
+	_, err = NewNamedStudent([][]string{{"subject1", "g1", "2"}}, "j.doe")
+	if !cmp.Equal(err, &StudentError{Err: ErrWrongPriority, Name: "j.doe"}, cmp.Comparer(tools.CompareErrors)) {
+		t.Errorf("NewNamedStudent() error = %v, err %v", err, ErrWrongPriority)
+	}
+}
+
 func TestStudent_validate(t *testing.T) {
 	tests := []struct {
 		name string
@@ -150,6 +172,60 @@ func TestStudent_validate(t *testing.T) {
 	}
 }
 
+func TestStudent_Enrolled(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  string
+		s    *Student
+		want bool
+	}{
+		{
+			name: "Student is enrolled in a subject with priorities",
+			sub:  "Math",
+			s: &Student{
+				Preferences: map[SubjectGroup]int{
+					{"Math", "1"}: 1,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "Student is not enrolled in a subject without priorities",
+			sub:  "Physics",
+			s: &Student{
+				Preferences: map[SubjectGroup]int{
+					{"Math", "1"}: 1,
+				},
+			},
+		},
+		{
+			name: "Student is enrolled in a subject from their list",
+			sub:  "Physics",
+			s: &Student{
+				Subjects: []string{"Physics"},
+			},
+			want: true,
+		},
+		{
+			name: "Student is not enrolled in a subject missing from their list",
+			sub:  "Math",
+			s: &Student{
+				Subjects: []string{"Physics"},
+				Preferences: map[SubjectGroup]int{
+					{"Math", "1"}: 1,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Enrolled(tt.sub); got != tt.want {
+				t.Errorf("Student.Enrolled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestStudent_GetPreferredGroup(t *testing.T) {
 	type args struct {
 		subject string
@@ -185,6 +261,37 @@ func TestStudent_GetPreferredGroup(t *testing.T) {
 			},
 			wantRes: "2",
 		},
+		{
+			name: "Skips groups without a priority",
+			args: args{
+				subject: "Math",
+				groups:  []string{"1", "2"},
+			},
+			s: &Student{
+				Preferences: map[SubjectGroup]int{
+					{
+						Subject: "Math",
+						Group:   "2",
+					}: 3,
+				},
+			},
+			wantRes: "2",
+		},
+		{
+			name: "Returns an empty name when no group has a priority",
+			args: args{
+				subject: "Math",
+				groups:  []string{"1", "2"},
+			},
+			s: &Student{
+				Preferences: map[SubjectGroup]int{
+					{
+						Subject: "Physics",
+						Group:   "1",
+					}: 1,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -342,7 +449,30 @@ func TestStudent_GetHappiness(t *testing.T) {
 
 func TestStudent_CalculateHappiness(t *testing.T) {
 	type args struct {
-		sn string
+		m   SatisfactionModel
+		sub *Subject
+	}
+	sub := &Subject{
+		Name: "Math",
+		Groups: []*Group{
+			{Name: "1"},
+			{Name: "2"},
+			{Name: "3"},
+		},
+	}
+	pref := map[SubjectGroup]int{
+		{
+			Subject: "Math",
+			Group:   "1",
+		}: 1,
+		{
+			Subject: "Math",
+			Group:   "2",
+		}: 2,
+		{
+			Subject: "Math",
+			Group:   "3",
+		}: 3,
 	}
 	tests := []struct {
 		name string
@@ -353,33 +483,75 @@ func TestStudent_CalculateHappiness(t *testing.T) {
 		{
 			name: "Successfully calculates student's happiness",
 			args: args{
-				sn: "Math",
+				m:   &LinearRank{},
+				sub: sub,
 			},
 			s: &Student{
-				Happiness: make(map[string]float64),
-				Preferences: map[SubjectGroup]int{
-					{
-						Subject: "Math",
-						Group:   "1",
-					}: 1,
-					{
-						Subject: "Math",
-						Group:   "2",
-					}: 2,
-					{
-						Subject: "Math",
-						Group:   "3",
-					}: 3,
+				Happiness:   make(map[string]float64),
+				Preferences: pref,
+				FinalGroups: map[string]*Group{
+					"Math": sub.Groups[1],
+				},
+			},
+			want: map[string]float64{
+				"Math": (2.0 / float64(3)) * 100.0,
+			},
+		},
+		{
+			name: "Student without a group is not happy",
+			args: args{
+				m:   &LinearRank{},
+				sub: sub,
+			},
+			s: &Student{
+				Happiness:   make(map[string]float64),
+				Preferences: pref,
+				FinalGroups: make(map[string]*Group),
+			},
+			want: map[string]float64{
+				"Math": 0,
+			},
+		},
+		{
+			name: "Uses bids with a bid satisfaction model",
+			args: args{
+				m:   &PointsShare{},
+				sub: sub,
+			},
+			s: &Student{
+				Happiness:   make(map[string]float64),
+				Preferences: pref,
+				Bids:        map[SubjectGroup]int{{"Math", "1"}: 40, {"Math", "2"}: 10},
+				FinalGroups: map[string]*Group{
+					"Math": sub.Groups[1],
+				},
+			},
+			want: map[string]float64{
+				"Math": 25.0,
+			},
+		},
+		{
+			name: "Uses priorities of a bidding student with a rank model",
+			args: args{
+				m:   &LinearRank{},
+				sub: sub,
+			},
+			s: &Student{
+				Happiness:   make(map[string]float64),
+				Preferences: pref,
+				Bids:        map[SubjectGroup]int{{"Math", "1"}: 40, {"Math", "2"}: 10},
+				FinalGroups: map[string]*Group{
+					"Math": sub.Groups[1],
 				},
 			},
 			want: map[string]float64{
-				"Math": (1.0 / float64(3)) * 100.0,
+				"Math": (2.0 / float64(3)) * 100.0,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.s.CalculateHappiness(tt.args.sn)
+			tt.s.CalculateHappiness(tt.args.m, tt.args.sub)
 			if !cmp.Equal(tt.s.Happiness, tt.want) {
 				t.Errorf("Student.CalculateHappiness() = %v, want %v", tt.s.Happiness, tt.want)
 			}
@@ -388,6 +560,8 @@ func TestStudent_CalculateHappiness(t *testing.T) {
 }
 
 func TestStudent_Save(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	twelve := time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC)
 	tests := []struct {
 		name string
 		s    *Student
@@ -397,17 +571,57 @@ func TestStudent_Save(t *testing.T) {
 			name: "Successfully saves groups for student",
 			s: &Student{
 				FinalGroups: map[string]*Group{
-					"Math":        {Name: "1"},
-					"Programming": {Name: "2a"},
-					"Algorithms":  nil,
+					"Math": {
+						Name:      "1",
+						Type:      Class,
+						Weekday:   time.Monday,
+						StartTime: ten,
+						EndTime:   twelve,
+						Place:     "A-1",
+					},
+					"Programming": {
+						Name:      "2a",
+						Type:      Laboratory,
+						Weekday:   time.Tuesday,
+						StartTime: ten,
+						EndTime:   twelve,
+						Place:     "B-2",
+						SubGroups: []*Group{
+							{
+								Name:      "2a",
+								Type:      Laboratory,
+								Weekday:   time.Thursday,
+								StartTime: ten,
+								EndTime:   twelve,
+								Place:     "B-3",
+							},
+						},
+					},
+					"Algorithms": nil,
+				},
+				FinalLectures: map[string]*Group{
+					"Programming": {
+						Name:      "Lecture B",
+						Type:      Lecture,
+						Weekday:   time.Friday,
+						StartTime: ten,
+						EndTime:   twelve,
+						Place:     "C-3",
+					},
 				},
 			},
 			want: [][]string{
 				{
-					"Math", "1",
+					"Math", "1", "Class", "Monday", "10:00", "12:00", "A-1",
+				},
+				{
+					"Programming", "Lecture B", "Lecture", "Friday", "10:00", "12:00", "C-3",
+				},
+				{
+					"Programming", "2a", "Laboratory", "Tuesday", "10:00", "12:00", "B-2",
 				},
 				{
-					"Programming", "2a",
+					"Programming", "2a", "Laboratory", "Thursday", "10:00", "12:00", "B-3",
 				},
 			},
 		},
@@ -420,3 +634,27 @@ func TestStudent_Save(t *testing.T) {
 		})
 	}
 }
+
+func TestStudent_Timetable(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	twelve := time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := &Student{
+		Name: "a",
+		Preferences: map[SubjectGroup]int{
+			{"Math", "1"}: 2,
+		},
+		FinalGroups: map[string]*Group{
+			"Math": {Name: "1", Type: Class, Weekday: time.Monday, StartTime: ten, EndTime: twelve, Place: "A-1"},
+		},
+		FinalLectures: map[string]*Group{
+			"Math": {Name: "Lecture", Type: Lecture, Weekday: time.Friday, StartTime: ten, EndTime: twelve, Place: "C-3"},
+		},
+	}
+	want := [][]string{
+		{"a", "Math", "Lecture", "Lecture", "Friday", "10:00", "12:00", "C-3", ""},
+		{"a", "Math", "1", "Class", "Monday", "10:00", "12:00", "A-1", "2"},
+	}
+	if got := s.Timetable(); !cmp.Equal(got, want) {
+		t.Errorf("Student.Timetable() = %v, want %v", got, want)
+	}
+}
@@ -41,6 +41,17 @@ func (s *Subject) GetGroup(gn string) *Group {
 	return nil
 }
 
+// GetLecture returns a lecture section which name is the same as passed.
+// It returns nil if a lecture section was not found.
+func (s *Subject) GetLecture(ln string) *Group {
+	for _, l := range s.Lectures {
+		if l.Name == ln {
+			return l
+		}
+	}
+	return nil
+}
+
 // Conflicts is used to calculate the number of conflicts within one subject.
 func (s *Subject) Conflicts() (res int) {
 	for _, g := range s.Groups {
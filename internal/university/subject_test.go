@@ -181,6 +181,50 @@ func TestSubject_GetGroup(t *testing.T) {
 	}
 }
 
+func TestSubject_GetLecture(t *testing.T) {
+	type args struct {
+		ln string
+	}
+	tests := []struct {
+		name string
+		args args
+		s    *Subject
+		want *Group
+	}{
+		{
+			name: "Returns nil because lecture doesn't exist",
+			s:    &Subject{},
+		},
+		{
+			name: "Successfully returns lecture",
+			args: args{
+				ln: "Lecture B",
+			},
+			s: &Subject{
+				Lectures: []*Group{
+					{
+						Name: "Lecture A",
+					},
+					{
+						Name: "Lecture B",
+					},
+				},
+			},
+			want: &Group{
+				Name: "Lecture B",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.s.GetLecture(tt.args.ln)
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("Subject.GetLecture() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSubject_Conflicts(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,96 @@
+package university
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ErrTeacherDoubleBooked is returned when a teacher teaches groups which are held in the same time.
+var ErrTeacherDoubleBooked = errors.New("teacher is booked into colliding groups")
+
+// TeacherError represents an error struct returned when a teacher has an incorrect timetable.
+type TeacherError struct {
+	Name string
+	Err  error
+}
+
+func (e *TeacherError) Error() string {
+	return fmt.Sprintf("incorrect timetable of teacher [%s]: %s", e.Name, e.Err.Error())
+}
+
+func (e *TeacherError) Unwrap() error {
+	return e.Err
+}
+
+// Teacher represents a teacher and all meetings which they teach.
+type Teacher struct {
+	Name     string
+	Meetings []*Booking
+}
+
+// Teachers returns all teachers of a schedule sorted by name, meetings without a teacher are skipped.
+func (s *Schedule) Teachers() []*Teacher {
+	teachers := make(map[string]*Teacher)
+	var res []*Teacher
+	for _, b := range s.bookings() {
+		n := b.Meeting.Teacher
+		if n == "" {
+			continue
+		}
+		t := teachers[n]
+		if t == nil {
+			t = &Teacher{Name: n}
+			teachers[n] = t
+			res = append(res, t)
+		}
+		t.Meetings = append(t.Meetings, b)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// ValidateTeachers checks if no teacher teaches two meetings which collide.
+// It returns TeacherError with ErrTeacherDoubleBooked for the first colliding meetings.
+func (s *Schedule) ValidateTeachers() error {
+	for _, t := range s.Teachers() {
+		if a, b := collide(t.Meetings); a != nil {
+			return &TeacherError{
+				Name: t.Name,
+				Err:  fmt.Errorf("%w: %s %s and %s %s on %s", ErrTeacherDoubleBooked, a.Subject, a.Group.Name, b.Subject, b.Group.Name, a.Meeting.Weekday),
+			}
+		}
+	}
+	return nil
+}
+
+// Hours returns the number of hours which a teacher teaches in an average week, see hours.
+func (t *Teacher) Hours() float64 {
+	return hours(t.Meetings)
+}
+
+// Save creates a slice with meetings taught by a teacher.
+// Each row contains teacher name, subject name, group name, class type, weekday, start time, end time, place, frequency
+// and the number of students enrolled in a group.
+func (t *Teacher) Save() [][]string {
+	res := make([][]string, len(t.Meetings))
+	for i, tm := range t.Meetings {
+		m := tm.Meeting
+		res[i] = []string{
+			t.Name,
+			tm.Subject,
+			tm.Group.Name,
+			string(tm.Group.Type),
+			m.Weekday.String(),
+			m.StartTime.Format(timeLayout),
+			m.EndTime.Format(timeLayout),
+			m.Place,
+			strconv.Itoa(m.Frequency),
+			strconv.Itoa(len(tm.Group.Students) + len(tm.Group.PriorityStudents)),
+		}
+	}
+	return res
+}
@@ -0,0 +1,150 @@
+package university
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestSchedule_Teachers(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	half := time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC)
+	l := &Group{Name: "Lecture", Type: Lecture, Teacher: "b", Weekday: time.Monday, StartTime: ten, EndTime: half, Frequency: 1}
+	sg := &Group{Name: "1", Type: Class, Teacher: "b", Weekday: time.Thursday, StartTime: ten, EndTime: half, Frequency: 1}
+	g := &Group{Name: "1", Type: Class, Teacher: "a", Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 2, SubGroups: []*Group{sg}}
+	s := &Schedule{
+		Subjects: []*Subject{
+			{
+				Name:     "Math",
+				Lectures: []*Group{l},
+				Groups: []*Group{
+					g,
+					{Name: "2", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 1},
+				},
+			},
+		},
+	}
+	want := []*Teacher{
+		{
+			Name: "a",
+			Meetings: []*Booking{
+				{Subject: "Math", Group: g, Meeting: g},
+			},
+		},
+		{
+			Name: "b",
+			Meetings: []*Booking{
+				{Subject: "Math", Group: l, Meeting: l},
+				{Subject: "Math", Group: g, Meeting: sg},
+			},
+		},
+	}
+	if got := s.Teachers(); !cmp.Equal(got, want) {
+		t.Errorf("Schedule.Teachers() diff = %s", cmp.Diff(got, want))
+	}
+}
+
+func TestSchedule_ValidateTeachers(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	half := time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		groups []*Group
+		err    error
+	}{
+		{
+			name: "Accepts teachers without colliding groups",
+			groups: []*Group{
+				{Name: "1", Type: Class, Teacher: "a", Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 1},
+				{Name: "2", Type: Class, Teacher: "a", Weekday: time.Wednesday, StartTime: ten, EndTime: half, Frequency: 1},
+			},
+		},
+		{
+			name: "Ignores groups without a teacher",
+			groups: []*Group{
+				{Name: "1", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 1},
+				{Name: "2", Type: Class, Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 1},
+			},
+		},
+		{
+			name: "Fails when a teacher is booked into colliding groups",
+			groups: []*Group{
+				{Name: "1", Type: Class, Teacher: "a", Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 2},
+				{Name: "2", Type: Class, Teacher: "a", Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 1},
+			},
+			err: &TeacherError{
+				Name: "a",
+				Err:  fmt.Errorf("%w: Math 1 and Math 2 on Tuesday", ErrTeacherDoubleBooked),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Schedule{Subjects: []*Subject{{Name: "Math", Groups: tt.groups}}}
+			if err := s.ValidateTeachers(); !tools.CompareErrors(err, tt.err) {
+				t.Errorf("Schedule.ValidateTeachers() error = %v, err %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTeacher_Hours(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	half := time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		meetings []*Group
+		want     float64
+	}{
+		{
+			name: "Counts a part of meetings held every few weeks",
+			meetings: []*Group{
+				{Weekday: time.Tuesday, StartTime: ten, EndTime: half, Frequency: 2},
+			},
+			want: 0.75,
+		},
+		{
+			name: "Sums meetings held every week",
+			meetings: []*Group{
+				{Weekday: time.Monday, StartTime: ten, EndTime: half, Frequency: 1},
+				{Weekday: time.Thursday, StartTime: ten, EndTime: half, Frequency: 1},
+			},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := &Teacher{Name: "a"}
+			for _, m := range tt.meetings {
+				tc.Meetings = append(tc.Meetings, &Booking{Subject: "Math", Group: m, Meeting: m})
+			}
+			if got := tc.Hours(); got != tt.want {
+				t.Errorf("Teacher.Hours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeacher_Save(t *testing.T) {
+	g := &Group{
+		Name:      "1",
+		Type:      Class,
+		Teacher:   "a",
+		Weekday:   time.Tuesday,
+		StartTime: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(0, 1, 1, 11, 30, 0, 0, time.UTC),
+		Place:     "A-1",
+		Frequency: 2,
+		Students:  []*Student{{Name: "x"}},
+	}
+	tc := &Teacher{Name: "a", Meetings: []*Booking{{Subject: "Math", Group: g, Meeting: g}}}
+	want := [][]string{
+		{"a", "Math", "1", "Class", "Tuesday", "10:00", "11:30", "A-1", "2", "1"},
+	}
+	if got := tc.Save(); !cmp.Equal(got, want) {
+		t.Errorf("Teacher.Save() = %v, want %v", got, want)
+	}
+}
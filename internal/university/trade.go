@@ -0,0 +1,169 @@
+package university
+
+import "fmt"
+
+// TradeRequest represents a student who wants to move to another group or lecture section of a subject.
+type TradeRequest struct {
+	Student *Student
+	Subject string
+	Group   string
+}
+
+// Trade represents students who exchanged groups of a subject in a cycle: every student takes the group of the next one
+// and the last student takes the group of the first one, so the number of students in groups does not change.
+// A trade of two students is a swap.
+type Trade struct {
+	Subject string
+	Moves   []*Event
+}
+
+// Save creates a slice with moves of a trade.
+// Each row contains subject name, class type, student name, previous group and new group.
+func (t *Trade) Save() [][]string {
+	res := make([][]string, len(t.Moves))
+	for i, m := range t.Moves {
+		res[i] = []string{t.Subject, string(m.Class), m.Student, m.From, m.To}
+	}
+	return res
+}
+
+// TradeReport represents the outcome of trading.
+// Trades - executed trades in order of execution.
+// Pending - requests which could not be fulfilled.
+type TradeReport struct {
+	Trades  []*Trade
+	Pending []*TradeRequest
+}
+
+// tradeNode is a trade request together with its subject, the current group and the wanted group of a student.
+type tradeNode struct {
+	req      *TradeRequest
+	sub      *Subject
+	from, to *Group
+}
+
+// tradeKey identifies a group or a lecture section of a subject of a student, a student moves once per key.
+type tradeKey struct {
+	student string
+	subject string
+	lecture bool
+}
+
+func (n *tradeNode) key() tradeKey {
+	return tradeKey{student: n.req.Student.Name, subject: n.sub.Name, lecture: n.to.Type == Lecture}
+}
+
+// Trade exchanges groups and lecture sections of students according to requests.
+// Requests form a directed graph in which every request points to requests of students who are in the wanted group.
+// Cycles of the graph are trades, the shortest ones are executed first and each one is executed at once.
+// A request is a part of a trade only when the wanted group does not collide with the timetable of a student,
+// see Student.CanMove, so capacities of groups are kept and no collisions are created.
+// Every student moves at most once in a group and once in a lecture section of a subject.
+// Executed moves are appended to History.
+// It returns StudentError with ErrUnknownSubject, ErrUnknownGroup or ErrNotEnrolled when any request is invalid,
+// nothing is traded then.
+func (s *Schedule) Trade(reqs []*TradeRequest) (*TradeReport, error) {
+	var nodes []*tradeNode
+	for _, r := range reqs {
+		sub := s.GetSubject(r.Subject)
+		if sub == nil {
+			return nil, &StudentError{Name: r.Student.Name, Err: fmt.Errorf("%w: %s", ErrUnknownSubject, r.Subject)}
+		}
+		to := sub.GetGroup(r.Group)
+		if to == nil {
+			to = sub.GetLecture(r.Group)
+		}
+		if to == nil {
+			return nil, &StudentError{Name: r.Student.Name, Err: fmt.Errorf("%w: %s %s", ErrUnknownGroup, r.Subject, r.Group)}
+		}
+		from := placement(r.Student, sub.Name, to.Type)
+		if from == nil {
+			return nil, &StudentError{Name: r.Student.Name, Err: ErrNotEnrolled}
+		}
+		nodes = append(nodes, &tradeNode{req: r, sub: sub, from: from, to: to})
+	}
+
+	res := &TradeReport{}
+	moved := make(map[tradeKey]bool)
+	done := make(map[*tradeNode]bool)
+	for {
+		c := shortestCycle(nodes, moved)
+		if c == nil {
+			break
+		}
+		t := &Trade{Subject: c[0].sub.Name}
+		for _, n := range c {
+			n.from.leave(n.req.Student)
+		}
+		for _, n := range c {
+			st := n.req.Student
+			st.place(n.sub.Name, n.to)
+			if n.to.Type != Lecture {
+				st.CalculateHappiness(s.satisfaction(), n.sub)
+			}
+			settle(st, n.sub, n.to)
+			moved[n.key()] = true
+			done[n] = true
+			s.record(EventTraded, st, n.sub, n.to.Type, n.from.Name, n.to.Name)
+			t.Moves = append(t.Moves, s.History[len(s.History)-1])
+		}
+		res.Trades = append(res.Trades, t)
+	}
+	for _, n := range nodes {
+		if !done[n] {
+			res.Pending = append(res.Pending, n.req)
+		}
+	}
+	return res, nil
+}
+
+// shortestCycle returns the shortest cycle of requests of students who did not move yet.
+// Ties are broken by the order of requests.
+func shortestCycle(nodes []*tradeNode, moved map[tradeKey]bool) []*tradeNode {
+	var active []*tradeNode
+	for _, n := range nodes {
+		if !moved[n.key()] && n.from != n.to && n.req.Student.canTake(n.sub.Name, n.to) {
+			active = append(active, n)
+		}
+	}
+	next := func(n *tradeNode) (res []*tradeNode) {
+		for _, m := range active {
+			if m.sub == n.sub && m.from == n.to && m.req.Student != n.req.Student {
+				res = append(res, m)
+			}
+		}
+		return
+	}
+	var best []*tradeNode
+	for _, start := range active {
+		// Breadth first search finds the shortest path from the start back to it
+		parent := map[*tradeNode]*tradeNode{start: nil}
+		queue := []*tradeNode{start}
+		var last *tradeNode
+		for len(queue) > 0 && last == nil {
+			n := queue[0]
+			queue = queue[1:]
+			for _, m := range next(n) {
+				if m == start {
+					last = n
+					break
+				}
+				if _, ok := parent[m]; !ok {
+					parent[m] = n
+					queue = append(queue, m)
+				}
+			}
+		}
+		if last == nil {
+			continue
+		}
+		var c []*tradeNode
+		for n := last; n != nil; n = parent[n] {
+			c = append([]*tradeNode{n}, c...)
+		}
+		if best == nil || len(c) < len(best) {
+			best = c
+		}
+	}
+	return best
+}
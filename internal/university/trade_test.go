@@ -0,0 +1,167 @@
+package university
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func TestSchedule_Trade(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		reqs      [][2]string
+		collision bool
+		groups    map[string]string
+		trades    [][][]string
+		pending   []string
+		err       error
+	}{
+		{
+			name:   "Swaps groups of two students",
+			reqs:   [][2]string{{"a", "2"}, {"b", "1"}},
+			groups: map[string]string{"a": "2", "b": "1", "c": "3"},
+			trades: [][][]string{
+				{{"Math", "Class", "a", "1", "2"}, {"Math", "Class", "b", "2", "1"}},
+			},
+		},
+		{
+			name:   "Exchanges groups in a cycle",
+			reqs:   [][2]string{{"a", "2"}, {"b", "3"}, {"c", "1"}},
+			groups: map[string]string{"a": "2", "b": "3", "c": "1"},
+			trades: [][][]string{
+				{{"Math", "Class", "a", "1", "2"}, {"Math", "Class", "b", "2", "3"}, {"Math", "Class", "c", "3", "1"}},
+			},
+		},
+		{
+			name:   "Executes the shortest cycle first",
+			reqs:   [][2]string{{"a", "2"}, {"b", "3"}, {"c", "1"}, {"b", "1"}},
+			groups: map[string]string{"a": "2", "b": "1", "c": "3"},
+			trades: [][][]string{
+				{{"Math", "Class", "a", "1", "2"}, {"Math", "Class", "b", "2", "1"}},
+			},
+			pending: []string{"b 3", "c 1"},
+		},
+		{
+			name:      "Skips requests which collide with a timetable",
+			reqs:      [][2]string{{"a", "2"}, {"b", "1"}},
+			collision: true,
+			groups:    map[string]string{"a": "1", "b": "2", "c": "3"},
+			pending:   []string{"a 2", "b 1"},
+		},
+		{
+			name:    "Keeps requests without a counterpart pending",
+			reqs:    [][2]string{{"a", "2"}, {"c", "3"}},
+			groups:  map[string]string{"a": "1", "b": "2", "c": "3"},
+			pending: []string{"a 2", "c 3"},
+		},
+		{
+			name:   "Fails on an unknown group",
+			reqs:   [][2]string{{"a", "2"}, {"b", "7"}},
+			groups: map[string]string{"a": "1", "b": "2", "c": "3"},
+			err:    &StudentError{Name: "b", Err: fmt.Errorf("%w: Math 7", ErrUnknownGroup)},
+		},
+		{
+			name:   "Fails on a student without a group",
+			reqs:   [][2]string{{"d", "1"}},
+			groups: map[string]string{"a": "1", "b": "2", "c": "3"},
+			err:    &StudentError{Name: "d", Err: ErrNotEnrolled},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Groups 1, 2 and 3 have one place each and students a, b and c are enrolled in them
+			s := &Schedule{
+				Subjects: []*Subject{
+					{
+						Name: "Math",
+						Groups: []*Group{
+							{Name: "1", Type: Class, Capacity: 1, Weekday: time.Monday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+							{Name: "2", Type: Class, Capacity: 1, Weekday: time.Tuesday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+							{Name: "3", Type: Class, Capacity: 1, Weekday: time.Wednesday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+						},
+					},
+				},
+			}
+			students := []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"1": 2, "2": 1, "3": 3}),
+			}
+			for i, st := range students {
+				st.place("Math", s.Subjects[0].Groups[i])
+			}
+			if tt.collision {
+				// Student b has another group at the same time as group 1
+				students[1].FinalGroups["Physics"] = &Group{Name: "1", Weekday: time.Monday, StartTime: ten, EndTime: ten.Add(time.Hour)}
+			}
+			byName := map[string]*Student{"d": newTestStudent("d", map[string]int{"1": 1})}
+			for _, st := range students {
+				byName[st.Name] = st
+			}
+			var reqs []*TradeRequest
+			for _, r := range tt.reqs {
+				reqs = append(reqs, &TradeRequest{Student: byName[r[0]], Subject: "Math", Group: r[1]})
+			}
+			got, err := s.Trade(reqs)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("Schedule.Trade() error = %v, err %v", err, tt.err)
+			}
+			groups := make(map[string]string)
+			for _, g := range s.Subjects[0].Groups {
+				for _, st := range g.Students {
+					groups[st.Name] = g.Name
+				}
+			}
+			if diff := cmp.Diff(tt.groups, groups); diff != "" {
+				t.Errorf("Schedule.Trade() groups mismatch (-want +got):\n%s", diff)
+			}
+			if got == nil {
+				return
+			}
+			var trades [][][]string
+			for _, tr := range got.Trades {
+				trades = append(trades, tr.Save())
+			}
+			if diff := cmp.Diff(tt.trades, trades); diff != "" {
+				t.Errorf("Schedule.Trade() trades mismatch (-want +got):\n%s", diff)
+			}
+			var pending []string
+			for _, r := range got.Pending {
+				pending = append(pending, r.Student.Name+" "+r.Group)
+			}
+			if diff := cmp.Diff(tt.pending, pending); diff != "" {
+				t.Errorf("Schedule.Trade() pending mismatch (-want +got):\n%s", diff)
+			}
+			for _, st := range students {
+				if g := st.FinalGroups["Math"]; g.Name != groups[st.Name] {
+					t.Errorf("Schedule.Trade() student %s final group = %s, want %s", st.Name, g.Name, groups[st.Name])
+				}
+			}
+			var moves int
+			for _, tr := range got.Trades {
+				moves += len(tr.Moves)
+			}
+			if len(s.History) != moves {
+				t.Errorf("Schedule.Trade() history = %d events, want %d", len(s.History), moves)
+			}
+		})
+	}
+}
+
+func TestTrade_Save(t *testing.T) {
+	tr := &Trade{
+		Subject: "Math",
+		Moves: []*Event{
+			{Type: EventTraded, Student: "a", Subject: "Math", Class: Class, From: "1", To: "2"},
+			{Type: EventTraded, Student: "b", Subject: "Math", Class: Class, From: "2", To: "1"},
+		},
+	}
+	want := [][]string{{"Math", "Class", "a", "1", "2"}, {"Math", "Class", "b", "2", "1"}}
+	if diff := cmp.Diff(want, tr.Save()); diff != "" {
+		t.Errorf("Trade.Save() mismatch (-want +got):\n%s", diff)
+	}
+}
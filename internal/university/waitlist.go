@@ -0,0 +1,235 @@
+package university
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrNotEnrolled is returned when a student is withdrawn from a subject in which they have no group.
+var ErrNotEnrolled = errors.New("student has no group of a subject")
+
+// EventType defines a type of a change of a student's groups after enrollment.
+type EventType string
+
+const (
+	// EventWithdrawn - a student left a group.
+	EventWithdrawn EventType = "withdrawn"
+	// EventPromoted - a student was moved from a waitlist to a group.
+	EventPromoted EventType = "promoted"
+	// EventTraded - a student exchanged a group with other students, see Schedule.Trade.
+	EventTraded EventType = "traded"
+)
+
+// Event represents a change of a student's group or lecture section after enrollment.
+// From and To - names of groups, From is empty when a promoted student had no group, To is empty when a student withdrew.
+type Event struct {
+	Time    time.Time
+	Type    EventType
+	Student string
+	Subject string
+	Class   ClassType
+	From    string
+	To      string
+}
+
+// Save creates a row with time, type, student name, subject name, class type, previous group and new group.
+func (e *Event) Save() []string {
+	return []string{e.Time.Format(time.RFC3339), string(e.Type), e.Student, e.Subject, string(e.Class), e.From, e.To}
+}
+
+// buildWaitlists creates waitlists of all groups and lecture sections after enrollment.
+// A student is waitlisted in every group which they ranked higher than their final group of the same type,
+// students without a group are waitlisted in every group which they ranked.
+// Waitlists are sorted by the priority which students set to a group, ties keep the order of students.
+func (s *Schedule) buildWaitlists(students []*Student) {
+	for _, sub := range s.Subjects {
+		for _, g := range append(append([]*Group{}, sub.Lectures...), sub.Groups...) {
+			g.Waitlist = nil
+			for _, st := range students {
+				if !st.Enrolled(sub.Name) {
+					continue
+				}
+				if p := st.Preferences[SubjectGroup{sub.Name, g.Name}]; p > 0 && p < placementRank(st, sub, g.Type) {
+					g.Waitlist = append(g.Waitlist, st)
+				}
+			}
+			sort.SliceStable(g.Waitlist, func(i, j int) bool {
+				return waitRank(g.Waitlist[i], sub, g) < waitRank(g.Waitlist[j], sub, g)
+			})
+		}
+	}
+}
+
+func waitRank(st *Student, sub *Subject, g *Group) int {
+	return st.Preferences[SubjectGroup{sub.Name, g.Name}]
+}
+
+// placement returns the final group or lecture section of a student, depending on type t.
+func placement(st *Student, sub string, t ClassType) *Group {
+	if t == Lecture {
+		return st.FinalLectures[sub]
+	}
+	return st.FinalGroups[sub]
+}
+
+// placementRank returns the priority of the final group of type t of a student, students without a group
+// rank it after all their priorities.
+func placementRank(st *Student, sub *Subject, t ClassType) int {
+	if g := placement(st, sub.Name, t); g != nil {
+		if p := st.Preferences[SubjectGroup{sub.Name, g.Name}]; p > 0 {
+			return p
+		}
+	}
+	return int(^uint(0) >> 1)
+}
+
+// Withdraw removes a student from their group and lecture section of subject sub.
+// Freed places are taken by the first waitlisted students whose timetables do not collide with them,
+// places freed by promoted students are filled in the same way.
+// Every change is appended to History and returned.
+// It returns StudentError with ErrUnknownSubject or ErrNotEnrolled when a student cannot be withdrawn.
+func (s *Schedule) Withdraw(st *Student, sub string) ([]*Event, error) {
+	subject := s.GetSubject(sub)
+	if subject == nil {
+		return nil, &StudentError{Name: st.Name, Err: ErrUnknownSubject}
+	}
+	var freed []*Group
+	for _, g := range []*Group{st.FinalLectures[sub], st.FinalGroups[sub]} {
+		if g != nil {
+			freed = append(freed, g)
+		}
+	}
+	if len(freed) == 0 {
+		return nil, &StudentError{Name: st.Name, Err: ErrNotEnrolled}
+	}
+
+	subs := []string{}
+	for _, o := range s.Subjects {
+		if o.Name != sub && st.Enrolled(o.Name) {
+			subs = append(subs, o.Name)
+		}
+	}
+	st.Subjects = subs
+	delete(st.FinalLectures, sub)
+	delete(st.FinalGroups, sub)
+	delete(st.Happiness, sub)
+	for _, g := range append(append([]*Group{}, subject.Lectures...), subject.Groups...) {
+		g.removeWaiting(st)
+	}
+
+	start := len(s.History)
+	for _, g := range freed {
+		g.leave(st)
+		s.record(EventWithdrawn, st, subject, g.Type, g.Name, "")
+	}
+	for _, g := range freed {
+		s.promote(subject, g)
+	}
+	return s.History[start:], nil
+}
+
+// promote fills free places of a group with waitlisted students whose timetables do not collide with it.
+func (s *Schedule) promote(sub *Subject, g *Group) {
+	s.fill(sub, g, func(st *Student, from, to *Group) {
+		var n string
+		if from != nil {
+			n = from.Name
+		}
+		s.record(EventPromoted, st, sub, to.Type, n, to.Name)
+	})
+}
+
+// fill moves the first waitlisted students whose timetables do not collide with group g to its free places.
+// Places freed by moved students are filled in the same way, moved is called for every move.
+func (s *Schedule) fill(sub *Subject, g *Group, moved func(st *Student, from, to *Group)) {
+	for g.Conflicts() < 0 {
+		var st *Student
+		for _, w := range g.Waitlist {
+			if w.canTake(sub.Name, g) {
+				st = w
+				break
+			}
+		}
+		if st == nil {
+			return
+		}
+		old := placement(st, sub.Name, g.Type)
+		if old != nil {
+			old.leave(st)
+		}
+		st.place(sub.Name, g)
+		settle(st, sub, g)
+		if g.Type != Lecture {
+			st.CalculateHappiness(s.satisfaction(), sub)
+		}
+		moved(st, old, g)
+		if old != nil {
+			s.fill(sub, old, moved)
+		}
+	}
+}
+
+// settle removes a student from waitlists of groups or lecture sections of a subject
+// which they ranked no higher than their new group g.
+func settle(st *Student, sub *Subject, g *Group) {
+	p := waitRank(st, sub, g)
+	for _, o := range append(append([]*Group{}, sub.Lectures...), sub.Groups...) {
+		if (o.Type == Lecture) == (g.Type == Lecture) && waitRank(st, sub, o) >= p {
+			o.removeWaiting(st)
+		}
+	}
+}
+
+func (s *Schedule) record(t EventType, st *Student, sub *Subject, c ClassType, from, to string) {
+	s.History = append(s.History, &Event{
+		Time:    time.Now(),
+		Type:    t,
+		Student: st.Name,
+		Subject: sub.Name,
+		Class:   c,
+		From:    from,
+		To:      to,
+	})
+}
+
+// canTake checks if group g of subject sub does not collide with other groups and lecture sections of a student.
+func (s *Student) canTake(sub string, g *Group) bool {
+	if g.Type != Lecture {
+		return s.CanMove(sub, g)
+	}
+	return s.canAttendLecture(sub, g)
+}
+
+// leave removes a student from regular and priority students of a group.
+func (g *Group) leave(st *Student) {
+	g.RemoveStudent(st)
+	var ps []*Student
+	for _, p := range g.PriorityStudents {
+		if p.Name != st.Name {
+			ps = append(ps, p)
+		}
+	}
+	g.PriorityStudents = ps
+}
+
+func (g *Group) removeWaiting(st *Student) {
+	var res []*Student
+	for _, w := range g.Waitlist {
+		if w.Name != st.Name {
+			res = append(res, w)
+		}
+	}
+	g.Waitlist = res
+}
+
+// SaveWaitlist creates a slice with waitlisted students of a group of subject sub in order.
+// Each row contains subject name, group name, class type, position starting from 1 and student name.
+func (g *Group) SaveWaitlist(sub string) [][]string {
+	res := make([][]string, len(g.Waitlist))
+	for i, st := range g.Waitlist {
+		res[i] = []string{sub, g.Name, string(g.Type), strconv.Itoa(i + 1), st.Name}
+	}
+	return res
+}
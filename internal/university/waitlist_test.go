@@ -0,0 +1,207 @@
+package university
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pbartkowicz/scheduler/test/tools"
+)
+
+func waitlists(s *Schedule) map[string][]string {
+	res := make(map[string][]string)
+	for _, g := range s.Subjects[0].Groups {
+		for _, st := range g.Waitlist {
+			res[g.Name] = append(res[g.Name], st.Name)
+		}
+	}
+	return res
+}
+
+func TestSchedule_buildWaitlists(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		students []*Student
+		groups   map[string]string
+		want     map[string][]string
+	}{
+		{
+			name: "Waitlists students in groups which they ranked higher than their group",
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2}),
+			},
+			groups: map[string]string{"a": "1", "b": "2"},
+			want: map[string][]string{
+				"1": {"b"},
+			},
+		},
+		{
+			name: "Waitlists students without a group in every group which they ranked",
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				newTestStudent("b", map[string]int{"1": 2, "2": 1}),
+			},
+			groups: map[string]string{"a": "1"},
+			want: map[string][]string{
+				"1": {"b"},
+				"2": {"b"},
+			},
+		},
+		{
+			name: "Sorts waitlists by priority",
+			students: []*Student{
+				newTestStudent("a", map[string]int{"1": 1}),
+				newTestStudent("b", map[string]int{"1": 2, "2": 1}),
+				newTestStudent("c", map[string]int{"1": 1, "2": 2}),
+			},
+			groups: map[string]string{"a": "1"},
+			want: map[string][]string{
+				"1": {"c", "b"},
+				"2": {"b", "c"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &Subject{
+				Name: "Math",
+				Groups: []*Group{
+					{Name: "1", Type: Class, Capacity: 1, Weekday: time.Monday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+					{Name: "2", Type: Class, Capacity: 1, Weekday: time.Tuesday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+				},
+			}
+			for _, st := range tt.students {
+				st.place("Math", sub.GetGroup(tt.groups[st.Name]))
+			}
+			s := &Schedule{Subjects: []*Subject{sub}}
+			s.buildWaitlists(tt.students)
+			if diff := cmp.Diff(tt.want, waitlists(s)); diff != "" {
+				t.Errorf("Schedule.buildWaitlists() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchedule_Withdraw(t *testing.T) {
+	ten := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		student   int
+		subject   string
+		collision bool
+		groups    map[string]string
+		waitlists map[string][]string
+		events    []*Event
+		err       error
+	}{
+		{
+			name:      "Promotes waitlisted students to freed groups",
+			student:   0,
+			subject:   "Math",
+			groups:    map[string]string{"b": "1", "c": "2"},
+			waitlists: map[string][]string{},
+			events: []*Event{
+				{Type: EventWithdrawn, Student: "a", Subject: "Math", Class: Class, From: "1"},
+				{Type: EventPromoted, Student: "b", Subject: "Math", Class: Class, From: "2", To: "1"},
+				{Type: EventPromoted, Student: "c", Subject: "Math", Class: Class, From: "3", To: "2"},
+			},
+		},
+		{
+			name:      "Skips waitlisted students with colliding groups",
+			student:   0,
+			subject:   "Math",
+			collision: true,
+			groups:    map[string]string{"b": "2", "c": "1"},
+			waitlists: map[string][]string{
+				"1": {"b"},
+				"2": {"c"},
+			},
+			events: []*Event{
+				{Type: EventWithdrawn, Student: "a", Subject: "Math", Class: Class, From: "1"},
+				{Type: EventPromoted, Student: "c", Subject: "Math", Class: Class, From: "3", To: "1"},
+			},
+		},
+		{
+			name:    "Fails on an unknown subject",
+			student: 0,
+			subject: "Physics",
+			groups:  map[string]string{"a": "1", "b": "2", "c": "3"},
+			waitlists: map[string][]string{
+				"1": {"b", "c"},
+				"2": {"c"},
+			},
+			err: &StudentError{Name: "a", Err: ErrUnknownSubject},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Groups 1, 2 and 3 have one place each and students a, b and c are enrolled in them
+			s := &Schedule{
+				Subjects: []*Subject{
+					{
+						Name: "Math",
+						Groups: []*Group{
+							{Name: "1", Type: Class, Capacity: 1, Weekday: time.Monday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+							{Name: "2", Type: Class, Capacity: 1, Weekday: time.Tuesday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+							{Name: "3", Type: Class, Capacity: 1, Weekday: time.Wednesday, StartTime: ten, EndTime: ten.Add(time.Hour)},
+						},
+					},
+				},
+			}
+			students := []*Student{
+				newTestStudent("a", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("b", map[string]int{"1": 1, "2": 2, "3": 3}),
+				newTestStudent("c", map[string]int{"1": 2, "2": 1, "3": 3}),
+			}
+			for i, st := range students {
+				st.place("Math", s.Subjects[0].Groups[i])
+			}
+			s.buildWaitlists(students)
+			if tt.collision {
+				// Student b has another group at the same time as group 1
+				students[1].FinalGroups["Physics"] = &Group{Name: "1", Weekday: time.Monday, StartTime: ten, EndTime: ten.Add(time.Hour)}
+			}
+			got, err := s.Withdraw(students[tt.student], tt.subject)
+			if !cmp.Equal(err, tt.err, cmp.Comparer(tools.CompareErrors)) {
+				t.Errorf("Schedule.Withdraw() error = %v, err %v", err, tt.err)
+			}
+			if diff := cmp.Diff(tt.events, got, cmpopts.IgnoreFields(Event{}, "Time")); diff != "" {
+				t.Errorf("Schedule.Withdraw() events mismatch (-want +got):\n%s", diff)
+			}
+			groups := make(map[string]string)
+			for _, g := range s.Subjects[0].Groups {
+				for _, st := range g.Students {
+					groups[st.Name] = g.Name
+				}
+			}
+			if diff := cmp.Diff(tt.groups, groups); diff != "" {
+				t.Errorf("Schedule.Withdraw() groups mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.waitlists, waitlists(s)); diff != "" {
+				t.Errorf("Schedule.Withdraw() waitlists mismatch (-want +got):\n%s", diff)
+			}
+			if len(got) != 0 && len(s.History) != len(got) {
+				t.Errorf("Schedule.Withdraw() history = %d events, want %d", len(s.History), len(got))
+			}
+		})
+	}
+}
+
+func TestEvent_Save(t *testing.T) {
+	e := &Event{
+		Time:    time.Date(2020, 3, 5, 10, 0, 0, 0, time.UTC),
+		Type:    EventPromoted,
+		Student: "a",
+		Subject: "Math",
+		Class:   Class,
+		From:    "2",
+		To:      "1",
+	}
+	want := []string{"2020-03-05T10:00:00Z", "promoted", "a", "Math", "Class", "2", "1"}
+	if diff := cmp.Diff(want, e.Save()); diff != "" {
+		t.Errorf("Event.Save() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,361 @@
+// Package validation checks groups, students, priority students and rooms files together
+// and collects every problem found in them.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+// Code is a machine-readable identifier of a problem.
+type Code string
+
+const (
+	// CodeMissingColumns - a row has fewer columns than required.
+	CodeMissingColumns Code = "missing_columns"
+	// CodeWrongClassType - a class type is incorrect.
+	CodeWrongClassType Code = "wrong_class_type"
+	// CodeWrongWeekday - a weekday is incorrect.
+	CodeWrongWeekday Code = "wrong_weekday"
+	// CodeWrongTime - a start or end time has incorrect format.
+	CodeWrongTime Code = "wrong_time"
+	// CodeWrongTimeRange - an end time is not after a start time.
+	CodeWrongTimeRange Code = "end_before_start"
+	// CodeWrongDate - a start date has incorrect format.
+	CodeWrongDate Code = "wrong_date"
+	// CodeWrongFrequency - a frequency is not a number or is lower than 1.
+	CodeWrongFrequency Code = "wrong_frequency"
+	// CodeWrongCapacity - a capacity is not a number or is lower than 1.
+	CodeWrongCapacity Code = "wrong_capacity"
+	// CodeDuplicateGroup - a group meeting is defined more than once or a group is defined with different types.
+	CodeDuplicateGroup Code = "duplicate_group"
+	// CodeTeacherDoubleBooked - a teacher is booked into a group which collides with their other group.
+	CodeTeacherDoubleBooked Code = "teacher_double_booked"
+	// CodeRoomClash - a place is booked for a group which collides with another group in the same place.
+	CodeRoomClash Code = "room_clash"
+	// CodeRoomCapacityExceeded - capacity of a group is higher than capacity of its room.
+	CodeRoomCapacityExceeded Code = "room_capacity_exceeded"
+	// CodeWrongRoomCapacity - capacity of a room is not a number or is lower than 1.
+	CodeWrongRoomCapacity Code = "wrong_room_capacity"
+	// CodeUnknownSubject - a student chose a subject which does not exist in groups file.
+	CodeUnknownSubject Code = "unknown_subject"
+	// CodeUnknownGroup - a student set a priority to a group which does not exist in groups file.
+	CodeUnknownGroup Code = "unknown_group"
+	// CodeWrongPriority - a priority is not a number or priorities of a subject are not consecutive from 1.
+	CodeWrongPriority Code = "wrong_priority"
+	// CodeWrongBid - points of a bid are not a number or are lower than 1.
+	CodeWrongBid Code = "wrong_bid"
+	// CodeBudgetExceeded - a student bid more points than their budget.
+	CodeBudgetExceeded Code = "budget_exceeded"
+	// CodeNoPreferences - a student did not set any priority.
+	CodeNoPreferences Code = "no_preferences"
+	// CodeUnknownStudent - a priority student does not have a file with preferences.
+	CodeUnknownStudent Code = "unknown_student"
+	// CodeInvalidSchedule - a schedule could not be built from correct rows of groups file.
+	CodeInvalidSchedule Code = "invalid_schedule"
+)
+
+const (
+	groupColumns   = 11
+	studentColumns = 3
+	roomColumns    = 2
+)
+
+// Diagnostic describes one problem found in a file.
+// Row and Column are numbered from 1 as in a spreadsheet, 0 means that a problem concerns a whole file or row.
+type Diagnostic struct {
+	File    string `json:"file"`
+	Sheet   string `json:"sheet"`
+	Row     int    `json:"row"`
+	Column  int    `json:"column"`
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s [%s] row %d, column %d: %s: %s", d.File, d.Sheet, d.Row, d.Column, d.Code, d.Message)
+}
+
+// Table represents data read from one file.
+// Header - number of heading rows which were skipped when reading, it is used to calculate row numbers.
+// Name - name of a student whose preferences a table contains, if it is empty the name is taken from the file name.
+// Budget - points which a student can bid, when it is set a student table contains bids instead of priorities,
+// see university.NewBiddingStudent.
+type Table struct {
+	File   string
+	Sheet  string
+	Header int
+	Name   string
+	Budget int
+	Rows   [][]string
+}
+
+// StudentName returns the name of a student whose preferences a table contains.
+func (t *Table) StudentName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return strings.TrimSuffix(filepath.Base(t.File), filepath.Ext(t.File))
+}
+
+// Report contains all problems found during validation.
+type Report struct {
+	Diagnostics []*Diagnostic
+}
+
+// OK checks if no problems were found.
+func (r *Report) OK() bool {
+	return len(r.Diagnostics) == 0
+}
+
+// Save creates a slice with all problems, each row contains file, sheet, row, column, code and message.
+func (r *Report) Save() [][]string {
+	res := make([][]string, len(r.Diagnostics))
+	for i, d := range r.Diagnostics {
+		res[i] = []string{d.File, d.Sheet, strconv.Itoa(d.Row), strconv.Itoa(d.Column), string(d.Code), d.Message}
+	}
+	return res
+}
+
+func (r *Report) add(t *Table, row, col int, c Code, msg string) {
+	if row > 0 {
+		row += t.Header
+	}
+	r.Diagnostics = append(r.Diagnostics, &Diagnostic{
+		File:    t.File,
+		Sheet:   t.Sheet,
+		Row:     row,
+		Column:  col,
+		Code:    c,
+		Message: msg,
+	})
+}
+
+// Validate checks groups, students, priority students and rooms files together.
+// Each table has to contain rows in the format expected by university.NewGroup, university.NewStudent,
+// a list of priority students' names and rows expected by university.NewRoom respectively.
+// Priority students and rooms may be nil.
+func Validate(groups *Table, students []*Table, priority *Table, rooms *Table) *Report {
+	r := &Report{}
+	sch := validateGroups(r, groups, validateRooms(r, rooms))
+	names := make(map[string]bool)
+	for _, st := range students {
+		// Preferences cannot be checked without a schedule
+		if sch == nil {
+			names[st.StudentName()] = true
+			continue
+		}
+		names[validateStudent(r, sch, st)] = true
+	}
+	if priority != nil {
+		for i, p := range priority.Rows {
+			if len(p) == 0 || p[0] == "" {
+				r.add(priority, i+1, 1, CodeMissingColumns, "missing student name")
+				continue
+			}
+			if !names[p[0]] {
+				r.add(priority, i+1, 1, CodeUnknownStudent, fmt.Sprintf("student %s has no file with preferences", p[0]))
+			}
+		}
+	}
+	return r
+}
+
+// groupKey identifies one meeting of a group.
+type groupKey struct {
+	subject string
+	group   string
+	weekday string
+	start   string
+}
+
+// validateRooms checks every row of rooms file and returns correct rooms by name.
+func validateRooms(r *Report, t *Table) map[string]*university.Room {
+	rooms := make(map[string]*university.Room)
+	if t == nil {
+		return rooms
+	}
+	for i, rm := range t.Rows {
+		if len(rm) < roomColumns {
+			r.add(t, i+1, len(rm)+1, CodeMissingColumns, fmt.Sprintf("expected %d columns, got %d", roomColumns, len(rm)))
+			continue
+		}
+		nr, err := university.NewRoom(rm)
+		if err != nil {
+			r.add(t, i+1, 2, CodeWrongRoomCapacity, err.Error())
+			continue
+		}
+		rooms[nr.Name] = nr
+	}
+	return rooms
+}
+
+// validateGroups checks every row of groups file and returns a schedule built from the correct rows.
+// Capacity of groups is compared with capacity of passed rooms.
+// It returns nil when the schedule could not be built, the problem is reported for the whole file.
+func validateGroups(r *Report, t *Table, rooms map[string]*university.Room) *university.Schedule {
+	var valid [][]string
+	var booked []*booking
+	meetings := make(map[groupKey]bool)
+	types := make(map[[2]string]university.ClassType)
+	for i, g := range t.Rows {
+		if len(g) < groupColumns {
+			r.add(t, i+1, len(g)+1, CodeMissingColumns, fmt.Sprintf("expected %d columns, got %d", groupColumns, len(g)))
+			continue
+		}
+		ng, err := university.NewGroup(g)
+		if err != nil {
+			var ge *university.GroupError
+			if errors.As(err, &ge) {
+				r.add(t, i+1, ge.Column+1, groupCode(ge), ge.Err.Error())
+				continue
+			}
+			r.add(t, i+1, 0, CodeMissingColumns, err.Error())
+			continue
+		}
+		k := groupKey{g[0], g[9], g[3], g[4]}
+		if meetings[k] {
+			r.add(t, i+1, 10, CodeDuplicateGroup, fmt.Sprintf("group %s of %s is already defined on %s at %s", g[9], g[0], g[3], g[4]))
+			continue
+		}
+		meetings[k] = true
+		sg := [2]string{g[0], g[9]}
+		if tp, ok := types[sg]; ok && tp != ng.Type {
+			r.add(t, i+1, 2, CodeDuplicateGroup, fmt.Sprintf("group %s of %s is already defined as %s", g[9], g[0], tp))
+			continue
+		}
+		types[sg] = ng.Type
+		if b := doubleBooked(booked, ng); b != nil {
+			r.add(t, i+1, 3, CodeTeacherDoubleBooked, fmt.Sprintf("teacher %s already teaches group %s of %s on %s at %s", ng.Teacher, b.group.Name, b.subject, b.group.Weekday, b.group.StartTime.Format("15:04")))
+			continue
+		}
+		if b := clash(booked, ng); b != nil {
+			r.add(t, i+1, 7, CodeRoomClash, fmt.Sprintf("place %s is already booked for group %s of %s on %s at %s", ng.Place, b.group.Name, b.subject, b.group.Weekday, b.group.StartTime.Format("15:04")))
+			continue
+		}
+		if rm := rooms[ng.Place]; rm != nil && ng.Capacity > rm.Capacity {
+			r.add(t, i+1, 11, CodeRoomCapacityExceeded, fmt.Sprintf("group has %d places, room %s has %d", ng.Capacity, rm.Name, rm.Capacity))
+		}
+		booked = append(booked, &booking{g[0], ng})
+		valid = append(valid, g)
+	}
+	sch, err := university.NewSchedule(valid)
+	if err != nil {
+		r.add(t, 0, 0, CodeInvalidSchedule, err.Error())
+		return nil
+	}
+	return sch
+}
+
+// booking is a meeting of a group of a subject.
+type booking struct {
+	subject string
+	group   *university.Group
+}
+
+// doubleBooked returns a meeting of the same teacher which collides with a passed meeting.
+func doubleBooked(booked []*booking, g *university.Group) *booking {
+	if g.Teacher == "" {
+		return nil
+	}
+	for _, b := range booked {
+		if b.group.Teacher == g.Teacher && b.group.Collide(g) {
+			return b
+		}
+	}
+	return nil
+}
+
+// clash returns a meeting in the same place which collides with a passed meeting.
+func clash(booked []*booking, g *university.Group) *booking {
+	if g.Place == "" {
+		return nil
+	}
+	for _, b := range booked {
+		if b.group.Place == g.Place && b.group.Collide(g) {
+			return b
+		}
+	}
+	return nil
+}
+
+// groupCode returns a code of a problem found by university.NewGroup.
+func groupCode(ge *university.GroupError) Code {
+	switch {
+	case errors.Is(ge.Err, university.ErrWrongTimeRange):
+		return CodeWrongTimeRange
+	case ge.Column == 1:
+		return CodeWrongClassType
+	case ge.Column == 3:
+		return CodeWrongWeekday
+	case ge.Column == 4 || ge.Column == 5:
+		return CodeWrongTime
+	case ge.Column == 7:
+		return CodeWrongDate
+	case ge.Column == 8:
+		return CodeWrongFrequency
+	}
+	return CodeWrongCapacity
+}
+
+// ValidateStudent checks preferences of one student against subjects and groups of a schedule.
+// It is used to validate preferences as soon as they are submitted, before other files are complete.
+func ValidateStudent(sch *university.Schedule, t *Table) *Report {
+	r := &Report{}
+	validateStudent(r, sch, t)
+	return r
+}
+
+// validateStudent checks every row of a student file and returns the student name.
+func validateStudent(r *Report, sch *university.Schedule, t *Table) string {
+	n := t.StudentName()
+	if len(t.Rows) == 0 {
+		r.add(t, 0, 0, CodeNoPreferences, fmt.Sprintf("student %s did not set any priority", n))
+		return n
+	}
+	var valid [][]string
+	for i, p := range t.Rows {
+		if len(p) < studentColumns {
+			r.add(t, i+1, len(p)+1, CodeMissingColumns, fmt.Sprintf("expected %d columns, got %d", studentColumns, len(p)))
+			continue
+		}
+		v, err := strconv.Atoi(p[2])
+		if t.Budget > 0 && (err != nil || v < 1) {
+			r.add(t, i+1, 3, CodeWrongBid, fmt.Sprintf("bid %s is not a number of points of at least 1", p[2]))
+			continue
+		}
+		if err != nil {
+			r.add(t, i+1, 3, CodeWrongPriority, err.Error())
+			continue
+		}
+		sub := sch.GetSubject(p[0])
+		if sub == nil {
+			r.add(t, i+1, 1, CodeUnknownSubject, fmt.Sprintf("subject %s does not exist", p[0]))
+			continue
+		}
+		if sub.GetGroup(p[1]) == nil && sub.GetLecture(p[1]) == nil {
+			r.add(t, i+1, 2, CodeUnknownGroup, fmt.Sprintf("group %s of %s does not exist", p[1], p[0]))
+			continue
+		}
+		valid = append(valid, p)
+	}
+	if t.Budget > 0 {
+		var se *university.StudentError
+		if _, err := university.NewBiddingStudent(valid, n, t.Budget); errors.As(err, &se) && se.Err == university.ErrBudgetExceeded {
+			r.add(t, 0, 3, CodeBudgetExceeded, fmt.Sprintf("student %s bid more than %d points", n, t.Budget))
+		}
+		return n
+	}
+	if _, err := university.NewNamedStudent(valid, n); err != nil {
+		var se *university.StudentError
+		if errors.As(err, &se) {
+			r.add(t, 0, 3, CodeWrongPriority, se.Err.Error())
+		}
+	}
+	return n
+}
@@ -0,0 +1,228 @@
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pbartkowicz/scheduler/internal/university"
+)
+
+func TestDiagnostic_String(t *testing.T) {
+	d := &Diagnostic{
+		File:    "groups.xlsx",
+		Sheet:   "Sheet1",
+		Row:     3,
+		Column:  11,
+		Code:    CodeWrongCapacity,
+		Message: "incorrect capacity",
+	}
+	want := "groups.xlsx [Sheet1] row 3, column 11: wrong_capacity: incorrect capacity"
+	if got := d.String(); got != want {
+		t.Errorf("Diagnostic.String() = %v, want %v", got, want)
+	}
+}
+
+func TestDiagnostic_json(t *testing.T) {
+	d := &Diagnostic{
+		File:    "groups.xlsx",
+		Sheet:   "Sheet1",
+		Row:     3,
+		Column:  11,
+		Code:    CodeWrongCapacity,
+		Message: "incorrect capacity",
+	}
+	got, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"file":"groups.xlsx","sheet":"Sheet1","row":3,"column":11,"code":"wrong_capacity","message":"incorrect capacity"}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	groups := &Table{
+		File:   "groups.xlsx",
+		Sheet:  "Groups",
+		Header: 1,
+		Rows: [][]string{
+			{"Math", "Lecture", "teacher", "Monday", "9:30", "11:00", "A-1", "03-02-20", "1", "Lecture", "30"},
+			{"Math", "Class", "teacher", "Monday", "11:00", "12:30", "A-1", "03-02-20", "1", "1", "15"},
+			{"Math", "Class", "teacher", "Monday", "11:00", "12:30", "A-1", "03-02-20", "1", "1", "15"},
+			{"Math", "Laboratory", "teacher", "Friday", "11:00", "12:30", "A-1", "03-06-20", "1", "1", "15"},
+			{"Math", "Class", "teacher", "Tuesday", "12:30", "11:00", "A-1", "03-03-20", "1", "2", "15"},
+			{"Math", "Class", "teacher", "Tuesday", "12:30", "14:00", "A-1", "03-03-20", "0", "3", "15"},
+			{"Math", "Class", "teacher", "Tuesday", "14:00", "15:30", "A-1", "03-03-20", "1", "4", "0"},
+			{"Math", "Seminar", "teacher", "Tuesday", "14:00", "15:30", "A-1", "03-03-20", "1", "5", "10"},
+			{"Chemistry", "Class", "teacher", "Monday", "10:00", "11:30", "B-1", "03-02-20", "1", "1", "15"},
+			{"Biology", "Class", "other", "Monday", "10:00", "11:30", "A-1", "03-02-20", "1", "1", "15"},
+			{"Math", "Class", "teacher"},
+		},
+	}
+	students := []*Table{
+		{
+			File:   "students/aaa.xlsx",
+			Sheet:  "Sheet1",
+			Header: 1,
+			Rows: [][]string{
+				{"Math", "1", "1"},
+				{"Math", "Lecture", "1"},
+				{"Physics", "1", "1"},
+				{"Math", "2", "2"},
+				{"Math", "1", "x"},
+			},
+		},
+		{
+			File:   "students/bbb.xlsx",
+			Sheet:  "Sheet1",
+			Header: 1,
+			Rows: [][]string{
+				{"Math", "1", "2"},
+			},
+		},
+		{
+			File:   "students/ccc.xlsx",
+			Sheet:  "Sheet1",
+			Header: 1,
+		},
+	}
+	priority := &Table{
+		File:   "priority_students.xlsx",
+		Sheet:  "Sheet1",
+		Header: 1,
+		Rows: [][]string{
+			{"aaa"},
+			{"zzz"},
+		},
+	}
+	rooms := &Table{
+		File:   "rooms.xlsx",
+		Sheet:  "Rooms",
+		Header: 1,
+		Rows: [][]string{
+			{"A-1", "20", "projector"},
+			{"B-2", "x"},
+			{"C-3"},
+		},
+	}
+	want := []struct {
+		file string
+		row  int
+		col  int
+		code Code
+	}{
+		{"rooms.xlsx", 3, 2, CodeWrongRoomCapacity},
+		{"rooms.xlsx", 4, 2, CodeMissingColumns},
+		{"groups.xlsx", 2, 11, CodeRoomCapacityExceeded},
+		{"groups.xlsx", 4, 10, CodeDuplicateGroup},
+		{"groups.xlsx", 5, 2, CodeDuplicateGroup},
+		{"groups.xlsx", 6, 6, CodeWrongTimeRange},
+		{"groups.xlsx", 7, 9, CodeWrongFrequency},
+		{"groups.xlsx", 8, 11, CodeWrongCapacity},
+		{"groups.xlsx", 9, 2, CodeWrongClassType},
+		{"groups.xlsx", 10, 3, CodeTeacherDoubleBooked},
+		{"groups.xlsx", 11, 7, CodeRoomClash},
+		{"groups.xlsx", 12, 4, CodeMissingColumns},
+		{"students/aaa.xlsx", 4, 1, CodeUnknownSubject},
+		{"students/aaa.xlsx", 5, 2, CodeUnknownGroup},
+		{"students/aaa.xlsx", 6, 3, CodeWrongPriority},
+		{"students/bbb.xlsx", 0, 3, CodeWrongPriority},
+		{"students/ccc.xlsx", 0, 0, CodeNoPreferences},
+		{"priority_students.xlsx", 3, 1, CodeUnknownStudent},
+	}
+	r := Validate(groups, students, priority, rooms)
+	if r.OK() {
+		t.Fatalf("Validate() did not find any problem")
+	}
+	if len(r.Diagnostics) != len(want) {
+		for _, d := range r.Diagnostics {
+			t.Log(d)
+		}
+		t.Fatalf("Validate() found %d problems, want %d", len(r.Diagnostics), len(want))
+	}
+	for i, w := range want {
+		d := r.Diagnostics[i]
+		got := []interface{}{d.File, d.Row, d.Column, d.Code}
+		exp := []interface{}{w.file, w.row, w.col, w.code}
+		if !cmp.Equal(got, exp) {
+			t.Errorf("Validate() diagnostic %d = %v, want %v", i, got, exp)
+		}
+	}
+}
+
+func TestValidateStudent(t *testing.T) {
+	sch, err := university.NewSchedule([][]string{
+		{"Math", "Lecture", "teacher", "Monday", "9:30", "11:00", "A-1", "03-02-20", "1", "Lecture", "30"},
+		{"Math", "Class", "teacher", "Monday", "11:00", "12:30", "A-1", "03-02-20", "1", "1", "15"},
+	})
+	if err != nil {
+		t.Fatalf("NewSchedule() error = %v", err)
+	}
+	tests := []struct {
+		name  string
+		table *Table
+		want  []*Diagnostic
+	}{
+		{
+			name:  "Accepts correct preferences",
+			table: &Table{File: "students/j.doe", Name: "j.doe", Rows: [][]string{{"Math", "1", "1"}}},
+		},
+		{
+			name:  "Uses the student name instead of the file name",
+			table: &Table{File: "students/x.json", Name: "j.doe"},
+			want: []*Diagnostic{
+				{File: "students/x.json", Code: CodeNoPreferences, Message: "student j.doe did not set any priority"},
+			},
+		},
+		{
+			name:  "Finds unknown groups and wrong priorities",
+			table: &Table{File: "students/aaa.xlsx", Header: 1, Rows: [][]string{{"Math", "2", "1"}, {"Math", "1", "2"}}},
+			want: []*Diagnostic{
+				{File: "students/aaa.xlsx", Row: 2, Column: 2, Code: CodeUnknownGroup, Message: "group 2 of Math does not exist"},
+				{File: "students/aaa.xlsx", Column: 3, Code: CodeWrongPriority, Message: university.ErrWrongPriority.Error()},
+			},
+		},
+		{
+			name:  "Accepts bids within a budget",
+			table: &Table{File: "students/j.doe", Name: "j.doe", Budget: 100, Rows: [][]string{{"Math", "1", "60"}, {"Math", "Lecture", "40"}}},
+		},
+		{
+			name:  "Finds wrong bids and bids over a budget",
+			table: &Table{File: "students/aaa.xlsx", Header: 1, Budget: 50, Rows: [][]string{{"Math", "1", "60"}, {"Math", "Lecture", "0"}}},
+			want: []*Diagnostic{
+				{File: "students/aaa.xlsx", Row: 3, Column: 3, Code: CodeWrongBid, Message: "bid 0 is not a number of points of at least 1"},
+				{File: "students/aaa.xlsx", Column: 3, Code: CodeBudgetExceeded, Message: "student aaa bid more than 50 points"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateStudent(sch, tt.table).Diagnostics; !cmp.Equal(got, tt.want) {
+				t.Errorf("ValidateStudent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReport_Save(t *testing.T) {
+	r := &Report{
+		Diagnostics: []*Diagnostic{
+			{
+				File:    "students/aaa.xlsx",
+				Sheet:   "Sheet1",
+				Row:     2,
+				Column:  1,
+				Code:    CodeUnknownSubject,
+				Message: "subject Physics does not exist",
+			},
+		},
+	}
+	want := [][]string{
+		{"students/aaa.xlsx", "Sheet1", "2", "1", "unknown_subject", "subject Physics does not exist"},
+	}
+	if got := r.Save(); !cmp.Equal(got, want) {
+		t.Errorf("Report.Save() = %v, want %v", got, want)
+	}
+}
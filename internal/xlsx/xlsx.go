@@ -2,9 +2,15 @@
 package xlsx
 
 import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/360EntSecGroup-Skylar/excelize"
 )
@@ -20,6 +26,13 @@ var (
 	ErrRows = errors.New("could not read rows")
 )
 
+// Namespaces of parts of a workbook written by Workbook.
+const (
+	nsMain                 = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	nsRelationships        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	nsPackageRelationships = "http://schemas.openxmlformats.org/package/2006/relationships"
+)
+
 // Operation is a type of action which can be performed on a .xlsx file.
 // It is used in error messages.
 type Operation string
@@ -42,24 +55,64 @@ func (e *Error) Error() string {
 	return string(e.Op) + " " + e.File + ": " + e.Err.Error()
 }
 
+// Format implements format.Format for .xlsx files.
+type Format struct{}
+
+// ReadSheet implements format.Format.
+func (Format) ReadSheet(n string, skip bool) (string, [][]string, error) {
+	return ReadSheet(n, skip)
+}
+
+// Write implements format.Format.
+func (Format) Write(n, p, s string, dd [][]string) error {
+	return Write(n, p, s, dd)
+}
+
 // Read retrieves data from the first sheet of file n.
 // If skip is set to true, it skips the first line.
 func Read(n string, skip bool) ([][]string, error) {
+	_, data, err := ReadSheet(n, skip)
+	return data, err
+}
+
+// ReadSheet retrieves data from the first sheet of file n together with the name of the sheet.
+// If skip is set to true, it skips the first line.
+func ReadSheet(n string, skip bool) (string, [][]string, error) {
 	p, err := filepath.Abs(n)
 	if err != nil {
-		return nil, &Error{Op: ReadOp, File: n, Err: ErrPathNotExists}
+		return "", nil, &Error{Op: ReadOp, File: n, Err: ErrPathNotExists}
 	}
 	f, err := excelize.OpenFile(p)
 	if err != nil {
-		return nil, &Error{Op: ReadOp, File: n, Err: ErrFileNotExists}
+		return "", nil, &Error{Op: ReadOp, File: n, Err: ErrFileNotExists}
 	}
+	s, data, err := read(f, skip)
+	if err != nil {
+		return "", nil, &Error{Op: ReadOp, File: n, Err: err}
+	}
+	return s, data, nil
+}
+
+// ReadFrom retrieves data from the first sheet of a file read from r.
+// If skip is set to true, it skips the first line.
+func ReadFrom(r io.Reader, skip bool) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, ErrFileNotExists
+	}
+	_, data, err := read(f, skip)
+	return data, err
+}
+
+// read retrieves data from the first sheet of f together with the name of the sheet.
+func read(f *excelize.File, skip bool) (string, [][]string, error) {
 	s := f.GetSheetName(1)
 	if s == "" {
-		return nil, &Error{Op: ReadOp, File: n, Err: ErrSheetNotExists}
+		return "", nil, ErrSheetNotExists
 	}
 	rows, err := f.Rows(s)
 	if err != nil {
-		return nil, &Error{Op: ReadOp, File: n, Err: ErrRows}
+		return "", nil, ErrRows
 	}
 	// Skip heading
 	if skip {
@@ -69,7 +122,7 @@ func Read(n string, skip bool) ([][]string, error) {
 	for rows.Next() {
 		data = append(data, rows.Columns())
 	}
-	return data, nil
+	return s, data, nil
 }
 
 // Write creates a file with a given name in a given path and saves passed data in it.
@@ -91,3 +144,103 @@ func Write(n, p, s string, dd [][]string) error {
 	}
 	return f.SaveAs(fn)
 }
+
+// Workbook represents a .xlsx file with multiple sheets which is written through a stream.
+// Rows of every sheet are written to the underlying writer as soon as the sheet is added,
+// so the whole workbook is never kept in memory. The file is complete after Close.
+type Workbook struct {
+	z      *zip.Writer
+	c      io.Closer
+	sheets []string
+}
+
+// NewWorkbook creates a new instance of Workbook which is written to wr.
+func NewWorkbook(wr io.Writer) *Workbook {
+	return &Workbook{z: zip.NewWriter(wr)}
+}
+
+// CreateWorkbook creates a file with a given name in a given path and returns a Workbook which is written to it.
+func CreateWorkbook(n, p string) (*Workbook, error) {
+	rp, err := filepath.Abs(p)
+	if err != nil {
+		return nil, &Error{Op: WriteOp, File: p, Err: ErrPathNotExists}
+	}
+	f, err := os.Create(filepath.Join(rp, fmt.Sprintf("%s.xlsx", n)))
+	if err != nil {
+		return nil, &Error{Op: WriteOp, File: p, Err: err}
+	}
+	w := NewWorkbook(f)
+	w.c = f
+	return w, nil
+}
+
+// AddSheet writes sheet s with passed data to a workbook.
+// Every cell is saved as an inline string.
+func (w *Workbook) AddSheet(s string, dd [][]string) error {
+	w.sheets = append(w.sheets, s)
+	f, err := w.z.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return err
+	}
+	b := bufio.NewWriter(f)
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="` + nsMain + `"><sheetData>`)
+	for i, d := range dd {
+		fmt.Fprintf(b, `<row r="%d">`, i+1)
+		for j, v := range d {
+			fmt.Fprintf(b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, excelize.ToAlphaString(j), i+1)
+			xml.EscapeText(b, []byte(v))
+			b.WriteString(`</t></is></c>`)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.Flush()
+}
+
+// Close writes the parts of a workbook which list its sheets and finishes the file.
+// The file created by CreateWorkbook is closed as well.
+func (w *Workbook) Close() error {
+	var ct, wb, rels strings.Builder
+	ct.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	wb.WriteString(xml.Header + `<workbook xmlns="` + nsMain + `" xmlns:r="` + nsRelationships + `"><sheets>`)
+	rels.WriteString(xml.Header + `<Relationships xmlns="` + nsPackageRelationships + `">`)
+	for i, s := range w.sheets {
+		fmt.Fprintf(&ct, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		wb.WriteString(`<sheet name="`)
+		xml.EscapeText(&wb, []byte(s))
+		fmt.Fprintf(&wb, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, nsRelationships, i+1)
+	}
+	ct.WriteString(`</Types>`)
+	wb.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+	parts := []struct {
+		name, data string
+	}{
+		{"[Content_Types].xml", ct.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="` + nsPackageRelationships + `">` +
+			`<Relationship Id="rId1" Type="` + nsRelationships + `/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", wb.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+	}
+	for _, pt := range parts {
+		f, err := w.z.Create(pt.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, pt.data); err != nil {
+			return err
+		}
+	}
+	err := w.z.Close()
+	if w.c != nil {
+		if cerr := w.c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
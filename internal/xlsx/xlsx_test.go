@@ -1,11 +1,14 @@
 package xlsx
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/pbartkowicz/scheduler/test/tools"
 )
 
@@ -70,6 +73,19 @@ func TestRead(t *testing.T) {
 	}
 }
 
+func TestReadSheet(t *testing.T) {
+	s, got, err := ReadSheet("../../test/data/xlsx/read.xlsx", false)
+	if err != nil {
+		t.Fatalf("ReadSheet() error = %v", err)
+	}
+	if s == "" {
+		t.Errorf("ReadSheet() returned empty sheet name")
+	}
+	if len(got) != 4 {
+		t.Errorf("ReadSheet() got %d rows, want %d", len(got), 4)
+	}
+}
+
 func TestWrite(t *testing.T) {
 	p := "./tmp"
 	type args struct {
@@ -133,3 +149,67 @@ func TestWrite(t *testing.T) {
 	}
 	os.RemoveAll(p)
 }
+
+func TestWorkbook(t *testing.T) {
+	p := "./tmp"
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		os.Mkdir(p, os.ModePerm)
+	}
+	defer os.RemoveAll(p)
+	w, err := CreateWorkbook("results", p)
+	if err != nil {
+		t.Fatalf("CreateWorkbook() error = %v", err)
+	}
+	for _, sh := range []struct {
+		name string
+		rows [][]string
+	}{
+		{"Students", [][]string{{"a", "Math & Physics"}, {"b", ""}}},
+		{"Summary", [][]string{{"min", "10.00"}, {"max", "90.00"}}},
+	} {
+		if err := w.AddSheet(sh.name, sh.rows); err != nil {
+			t.Fatalf("Workbook.AddSheet() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Workbook.Close() error = %v", err)
+	}
+	f, err := excelize.OpenFile(fmt.Sprintf("%s/results.xlsx", p))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	want := map[string][][]string{
+		"Students": {{"a", "Math & Physics"}, {"b", ""}},
+		"Summary":  {{"min", "10.00"}, {"max", "90.00"}},
+	}
+	if got := f.GetSheetMap(); len(got) != len(want) {
+		t.Errorf("Workbook.Close() sheets = %v, want %d sheets", got, len(want))
+	}
+	for s, rows := range want {
+		if got := f.GetRows(s); !reflect.DeepEqual(got, rows) {
+			t.Errorf("Workbook.Close() sheet %s got = %v, want %v", s, got, rows)
+		}
+	}
+}
+
+func TestReadFrom(t *testing.T) {
+	var b bytes.Buffer
+	w := NewWorkbook(&b)
+	if err := w.AddSheet("Groups", [][]string{{"subject", "group"}, {"Math", "1"}, {"Physics", "2"}}); err != nil {
+		t.Fatalf("Workbook.AddSheet() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Workbook.Close() error = %v", err)
+	}
+	got, err := ReadFrom(&b, true)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	want := [][]string{{"Math", "1"}, {"Physics", "2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadFrom() got = %v, want %v", got, want)
+	}
+	if _, err := ReadFrom(strings.NewReader("not a workbook"), true); !tools.CompareErrors(err, ErrFileNotExists) {
+		t.Errorf("ReadFrom() error = %v, err %v", err, ErrFileNotExists)
+	}
+}